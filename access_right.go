@@ -0,0 +1,63 @@
+package fins
+
+import (
+	"context"
+)
+
+// AcquireAccessRight Acquires the access right of the PLC
+// while a device holds the access right, other devices can't change the operating mode or the program.
+// returns AccessRightError if another device holds it
+func (c *Client) AcquireAccessRight() error {
+	return c.AcquireAccessRightContext(context.Background())
+}
+
+// AcquireAccessRightContext same as AcquireAccessRight, stops waiting for the response when ctx is done
+func (c *Client) AcquireAccessRightContext(ctx context.Context) error {
+	return c.accessRight(ctx, CommandCodeAccessRightAcquire)
+}
+
+// ForcedAcquireAccessRight Acquires the access right even if another device holds it
+func (c *Client) ForcedAcquireAccessRight() error {
+	return c.ForcedAcquireAccessRightContext(context.Background())
+}
+
+// ForcedAcquireAccessRightContext same as ForcedAcquireAccessRight, stops waiting for the response when ctx is done
+func (c *Client) ForcedAcquireAccessRightContext(ctx context.Context) error {
+	return c.accessRight(ctx, CommandCodeAccessRightForcedAcquire)
+}
+
+// ReleaseAccessRight Releases the access right, nothing happens if the client doesn't hold it
+func (c *Client) ReleaseAccessRight() error {
+	return c.ReleaseAccessRightContext(context.Background())
+}
+
+// ReleaseAccessRightContext same as ReleaseAccessRight, stops waiting for the response when ctx is done
+func (c *Client) ReleaseAccessRightContext(ctx context.Context) error {
+	return c.accessRight(ctx, CommandCodeAccessRightRelease)
+}
+
+// WithAccessRight Acquires the access right, calls f and releases the access right even if f fails
+// returns AccessRightError without calling f if another device holds the access right
+func (c *Client) WithAccessRight(f func() error) error {
+	return c.WithAccessRightContext(context.Background(), f)
+}
+
+// WithAccessRightContext same as WithAccessRight, ctx is used to acquire the access right only
+// so that the access right is released even if ctx is done
+func (c *Client) WithAccessRightContext(ctx context.Context, f func() error) (err error) {
+	if err = c.AcquireAccessRightContext(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if er := c.ReleaseAccessRight(); err == nil {
+			err = er
+		}
+	}()
+	return f()
+}
+
+func (c *Client) accessRight(ctx context.Context, commandCode uint16) error {
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, accessRightCommand(commandCode, ProgramNumberAll)))
+	})
+}
@@ -0,0 +1,73 @@
+package fins
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// nodeTransport memoryTransport with another local node address
+type nodeTransport struct {
+	*memoryTransport
+	local DeviceAddress
+}
+
+func (t nodeTransport) Addresses() (local, remote DeviceAddress) {
+	_, remote = t.memoryTransport.Addresses()
+	return t.local, remote
+}
+
+func TestClient_AccessRight(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+	other := NewClient(func() (Transport, error) {
+		return nodeTransport{newMemoryTransport(s.handler), NewDeviceAddress(0, 9, 0)}, nil
+	})
+	defer other.Close()
+
+	assert.Nil(t, c.AcquireAccessRight())
+	assert.Nil(t, c.AcquireAccessRight(), "acquire again by the holder")
+
+	err := other.AcquireAccessRight()
+	var ae AccessRightError
+	assert.True(t, errors.As(err, &ae))
+	assert.Equal(t, NewDeviceAddress(0, 1, 0), ae.Holder())
+	assert.Equal(t, EndCodeAccessWriteErrorNoAccessRight, ae.EndCode())
+	assert.ErrorAs(t, other.Run(OperatingModeRun, ProgramNumberAll), &ae, "mode change needs the access right")
+
+	assert.Nil(t, other.ReleaseAccessRight(), "release by others does nothing")
+	assert.ErrorAs(t, other.AcquireAccessRight(), &ae)
+
+	assert.Nil(t, other.ForcedAcquireAccessRight())
+	assert.ErrorAs(t, c.Run(OperatingModeRun, ProgramNumberAll), &ae)
+	assert.Equal(t, NewDeviceAddress(0, 9, 0), ae.Holder())
+
+	assert.Nil(t, other.ReleaseAccessRight())
+	assert.Nil(t, c.Run(OperatingModeRun, ProgramNumberAll))
+}
+
+func TestClient_WithAccessRight(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	called := false
+	fErr := errors.New("recipe download failed")
+	err := c.WithAccessRight(func() error {
+		called = true
+		assert.NotNil(t, s.accessRight)
+		return fErr
+	})
+	assert.Equal(t, fErr, err)
+	assert.True(t, called)
+	assert.Nil(t, s.accessRight, "released even if f fails")
+
+	s.accessRight = &DeviceAddress{0, 9, 0}
+	called = false
+	err = c.WithAccessRight(func() error {
+		called = true
+		return nil
+	})
+	assert.ErrorAs(t, err, &AccessRightError{})
+	assert.False(t, called)
+}
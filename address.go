@@ -9,6 +9,11 @@ type DeviceAddress struct {
 	unit    byte
 }
 
+// NewDeviceAddress creates a FINS device address, used by custom Transport
+func NewDeviceAddress(network, node, unit byte) DeviceAddress {
+	return DeviceAddress{network: network, node: node, unit: unit}
+}
+
 // UDPAddress A full device address
 type UDPAddress struct {
 	deviceAddress DeviceAddress
@@ -28,3 +33,24 @@ func NewUDPAddress(ip string, port int, network, node, unit byte) UDPAddress {
 		},
 	}
 }
+
+// TCPAddress A full device address for FINS/TCP
+// node of the client TCPAddress is the FINS node address it asks for, 0 means auto allocated by the PLC
+type TCPAddress struct {
+	deviceAddress DeviceAddress
+	tcpAddress    *net.TCPAddr
+}
+
+func NewTCPAddress(ip string, port int, network, node, unit byte) TCPAddress {
+	return TCPAddress{
+		tcpAddress: &net.TCPAddr{
+			IP:   net.ParseIP(ip),
+			Port: port,
+		},
+		deviceAddress: DeviceAddress{
+			network: network,
+			node:    node,
+			unit:    unit,
+		},
+	}
+}
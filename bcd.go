@@ -0,0 +1,141 @@
+package fins
+
+import (
+	"context"
+	"encoding/binary"
+)
+
+// ReadBCD16 Reads a 4-digit BCD word
+// Example:
+//
+//	D100=0x1234, ReadBCD16(D, 100) returns 1234
+func (c *Client) ReadBCD16(memoryArea byte, address uint16) (uint16, error) {
+	return c.ReadBCD16Context(context.Background(), memoryArea, address)
+}
+
+// ReadBCD16Context same as ReadBCD16, stops waiting for the response when ctx is done
+func (c *Client) ReadBCD16Context(ctx context.Context, memoryArea byte, address uint16) (uint16, error) {
+	values, err := c.ReadBCD16sContext(ctx, memoryArea, address, 1)
+	if err != nil {
+		return 0, err
+	}
+	return values[0], nil
+}
+
+// ReadBCD16s Reads 4-digit BCD words, returns BCDBadDigitError if a word has a digit above 9
+// digits are in PLC order whatever SetByteOrder is
+func (c *Client) ReadBCD16s(memoryArea byte, address uint16, readCount uint16) ([]uint16, error) {
+	return c.ReadBCD16sContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadBCD16sContext same as ReadBCD16s, stops waiting for the response when ctx is done
+func (c *Client) ReadBCD16sContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]uint16, error) {
+	b, err := c.ReadBytesContext(ctx, memoryArea, address, readCount)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]uint16, len(b)/2)
+	for i := range values {
+		v, err := decodeBCDFixed(b[i*2 : i*2+2])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = uint16(v)
+	}
+	return values, nil
+}
+
+// ReadBCD32 Reads an 8-digit BCD double word, words are in the order set by SetWordOrder
+// Example:
+//
+//	D100=0x5678, D101=0x1234, ReadBCD32(D, 100) returns 12345678
+func (c *Client) ReadBCD32(memoryArea byte, address uint16) (uint32, error) {
+	return c.ReadBCD32Context(context.Background(), memoryArea, address)
+}
+
+// ReadBCD32Context same as ReadBCD32, stops waiting for the response when ctx is done
+func (c *Client) ReadBCD32Context(ctx context.Context, memoryArea byte, address uint16) (uint32, error) {
+	values, err := c.ReadBCD32sContext(ctx, memoryArea, address, 1)
+	if err != nil {
+		return 0, err
+	}
+	return values[0], nil
+}
+
+// ReadBCD32s Reads 8-digit BCD double words, returns BCDBadDigitError if a value has a digit above 9
+func (c *Client) ReadBCD32s(memoryArea byte, address uint16, readCount uint16) ([]uint32, error) {
+	return c.ReadBCD32sContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadBCD32sContext same as ReadBCD32s, stops waiting for the response when ctx is done
+func (c *Client) ReadBCD32sContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]uint32, error) {
+	raw, err := c.ReadUint32sContext(ctx, memoryArea, address, readCount)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]uint32, len(raw))
+	for i, r := range raw {
+		v, err := decodeBCDFixed(binary.BigEndian.AppendUint32(nil, r))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = uint32(v)
+	}
+	return values, nil
+}
+
+// WriteBCD16 Writes a 4-digit BCD word, returns BCDOverflowError if value is above 9999
+func (c *Client) WriteBCD16(memoryArea byte, address uint16, value uint16) error {
+	return c.WriteBCD16Context(context.Background(), memoryArea, address, value)
+}
+
+// WriteBCD16Context same as WriteBCD16, stops waiting for the response when ctx is done
+func (c *Client) WriteBCD16Context(ctx context.Context, memoryArea byte, address uint16, value uint16) error {
+	return c.WriteBCD16sContext(ctx, memoryArea, address, []uint16{value})
+}
+
+// WriteBCD16s Writes 4-digit BCD words, returns BCDOverflowError if a value is above 9999
+func (c *Client) WriteBCD16s(memoryArea byte, address uint16, data []uint16) error {
+	return c.WriteBCD16sContext(context.Background(), memoryArea, address, data)
+}
+
+// WriteBCD16sContext same as WriteBCD16s, stops waiting for the response when ctx is done
+func (c *Client) WriteBCD16sContext(ctx context.Context, memoryArea byte, address uint16, data []uint16) error {
+	b := make([]byte, 0, len(data)*2)
+	for _, v := range data {
+		bcd, err := encodeBCDFixed(uint64(v), 2)
+		if err != nil {
+			return err
+		}
+		b = append(b, bcd...)
+	}
+	return c.WriteBytesContext(ctx, memoryArea, address, b)
+}
+
+// WriteBCD32 Writes an 8-digit BCD double word, returns BCDOverflowError if value is above 99999999
+func (c *Client) WriteBCD32(memoryArea byte, address uint16, value uint32) error {
+	return c.WriteBCD32Context(context.Background(), memoryArea, address, value)
+}
+
+// WriteBCD32Context same as WriteBCD32, stops waiting for the response when ctx is done
+func (c *Client) WriteBCD32Context(ctx context.Context, memoryArea byte, address uint16, value uint32) error {
+	return c.WriteBCD32sContext(ctx, memoryArea, address, []uint32{value})
+}
+
+// WriteBCD32s Writes 8-digit BCD double words, returns BCDOverflowError if a value is above 99999999
+func (c *Client) WriteBCD32s(memoryArea byte, address uint16, data []uint32) error {
+	return c.WriteBCD32sContext(context.Background(), memoryArea, address, data)
+}
+
+// WriteBCD32sContext same as WriteBCD32s, stops waiting for the response when ctx is done
+func (c *Client) WriteBCD32sContext(ctx context.Context, memoryArea byte, address uint16, data []uint32) error {
+	values := make([]uint32, len(data))
+	for i, v := range data {
+		bcd, err := encodeBCDFixed(uint64(v), 4)
+		if err != nil {
+			return err
+		}
+		values[i] = binary.BigEndian.Uint32(bcd)
+	}
+	return c.WriteUint32sContext(ctx, memoryArea, address, values)
+}
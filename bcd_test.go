@@ -0,0 +1,94 @@
+package fins
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_decodeBCDFixed(t *testing.T) {
+	x, err := decodeBCDFixed([]byte{0x12, 0x34, 0x56, 0x78})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(12345678), x)
+
+	// 0xF is a bad digit in fixed size BCD
+	_, err = decodeBCDFixed([]byte{0x12, 0x3f})
+	assert.Equal(t, BCDBadDigitError{"lo", 0x0f}, err)
+	_, err = decodeBCDFixed([]byte{0xa2, 0x34})
+	assert.Equal(t, BCDBadDigitError{"hi", 0x0a}, err)
+}
+
+func TestClient_BCD16(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	assert.Nil(t, c.WriteBCD16(MemoryAreaDMWord, 100, 1234))
+	words, err := c.ReadWords(MemoryAreaDMWord, 100, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0x1234}, words)
+	v, err := c.ReadBCD16(MemoryAreaDMWord, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(1234), v)
+
+	assert.Nil(t, c.WriteBCD16s(MemoryAreaDMWord, 101, []uint16{0, 9999}))
+	values, err := c.ReadBCD16s(MemoryAreaDMWord, 100, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{1234, 0, 9999}, values)
+
+	assert.Equal(t, BCDOverflowError{}, c.WriteBCD16(MemoryAreaDMWord, 100, 10000))
+	assert.Nil(t, c.WriteWords(MemoryAreaDMWord, 100, []uint16{0x12a4}))
+	_, err = c.ReadBCD16(MemoryAreaDMWord, 100)
+	assert.Equal(t, BCDBadDigitError{"hi", 0x0a}, err)
+}
+
+func TestClient_BCD32(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	// low word first by default
+	assert.Nil(t, c.WriteBCD32(MemoryAreaDMWord, 100, 12345678))
+	words, err := c.ReadWords(MemoryAreaDMWord, 100, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0x5678, 0x1234}, words)
+	v, err := c.ReadBCD32(MemoryAreaDMWord, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(12345678), v)
+
+	c.SetWordOrder(WordOrderABCD)
+	assert.Nil(t, c.WriteBCD32s(MemoryAreaDMWord, 102, []uint32{99999999, 1}))
+	words, err = c.ReadWords(MemoryAreaDMWord, 102, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0x9999, 0x9999, 0x0000, 0x0001}, words)
+	values, err := c.ReadBCD32s(MemoryAreaDMWord, 102, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{99999999, 1}, values)
+
+	assert.Equal(t, BCDOverflowError{}, c.WriteBCD32(MemoryAreaDMWord, 100, 100000000))
+	assert.Nil(t, c.WriteWords(MemoryAreaDMWord, 100, []uint16{0x1234, 0x567f}))
+	_, err = c.ReadBCD32(MemoryAreaDMWord, 100)
+	assert.Equal(t, BCDBadDigitError{"lo", 0x0f}, err)
+}
+
+func TestClient_BCD_byteOrder(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	// BCD digits are in PLC order whatever the byte order of words is
+	c.SetByteOrder(binary.LittleEndian)
+	assert.Nil(t, c.WriteBCD16s(MemoryAreaDMWord, 100, []uint16{1234, 9870}))
+	b, err := c.ReadBytes(MemoryAreaDMWord, 100, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x12, 0x34, 0x98, 0x70}, b)
+	values, err := c.ReadBCD16s(MemoryAreaDMWord, 100, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{1234, 9870}, values)
+
+	assert.Nil(t, c.WriteBCD32(MemoryAreaDMWord, 110, 12345678))
+	v, err := c.ReadBCD32(MemoryAreaDMWord, 110)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(12345678), v)
+	b, err = c.ReadBytes(MemoryAreaDMWord, 110, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x56, 0x78, 0x12, 0x34}, b)
+}
@@ -0,0 +1,917 @@
+package fins
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultResponseTimeoutMillisecond uint = 20 // ms
+
+	// DefaultMaxWordsPerFrame max words of one memory area read or write frame of CS/CJ CPU units over Ethernet,
+	// they accept 999 words per read and 996 words per write
+	DefaultMaxWordsPerFrame = 996
+)
+
+// Transport carries FINS frames (FINS header + command or response) between a Client and a PLC.
+// Client calls Receive from several goroutines at the same time, so Receive must be concurrent safe
+type Transport interface {
+	// Send sends one FINS command frame
+	Send(frame []byte) error
+	// Receive reads one FINS response frame into buf and returns its length.
+	// after Close, Receive must return an error wrapping net.ErrClosed.
+	// io.EOF means the peer closed the transport and TransportBrokenError means it can't be used anymore,
+	// Client will dial a new one on next operation
+	Receive(buf []byte) (int, error)
+	// Close closes the transport and unblocks Receive
+	Close() error
+	// Addresses returns the FINS addresses of the client and the PLC put in frame header
+	Addresses() (local, remote DeviceAddress)
+}
+
+// DialFunc opens a new Transport.
+// Client dials lazily before the first operation and again after Close
+type DialFunc func() (Transport, error)
+
+// Client Omron FINS client, work over any Transport
+// this is concurrent safe
+type Client struct {
+	dial DialFunc
+	// config
+	responseTimeout  atomic.Int64
+	byteOrder        atomic.Value // type: binary.ByteOrder
+	readGoroutineNum atomic.Int32
+	dialect          atomic.Int32 // type: Dialect
+	wordOrder        atomic.Int32 // type: WordOrder
+	maxWordsPerFrame atomic.Int32
+
+	commLogger
+
+	sid  atomicByte
+	resp syncRespSlice
+
+	sf      singleflightOne // avoid Close call twice
+	closing atomic.Bool
+	wg      sync.WaitGroup
+
+	m         sync.Mutex
+	transport Transport
+	local     DeviceAddress
+	remote    DeviceAddress
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	ignoreErrorCode atomic.Value // map[uint16]struct{}{}
+}
+
+// NewClient creates a new Omron FINS client over transports opened by dial
+func NewClient(dial DialFunc) *Client {
+	c := &Client{}
+	c.init(dial)
+	return c
+}
+
+func (c *Client) init(dial DialFunc) {
+	c.dial = dial
+	c.SetTimeoutMs(defaultResponseTimeoutMillisecond)
+	c.SetReadPacketErrorLogger(&stdoutLogger{})
+	c.SetByteOrder(binary.BigEndian)
+	c.SetReadGoroutineNum(8)
+	c.SetMaxWordsPerFrame(DefaultMaxWordsPerFrame)
+
+	c.setTransportAndCtx(nil)
+}
+
+// ReadWords Reads words from the PLC data area
+func (c *Client) ReadWords(memoryArea byte, address uint16, readCount uint16) ([]uint16, error) {
+	return c.ReadWordsContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadWordsContext same as ReadWords, stops waiting for the response when ctx is done
+func (c *Client) ReadWordsContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]uint16, error) {
+	readBytes, err := c.ReadBytesContext(ctx, memoryArea, address, readCount)
+	if err != nil {
+		return nil, err
+	}
+	return c.bytesToUint16s(readBytes), nil
+}
+
+// ReadDoubleWords Reads double words from the PLC data area, only index registers (MemoryAreaIndexRegisterPV) are double word area
+func (c *Client) ReadDoubleWords(memoryArea byte, address uint16, readCount uint16) ([]uint32, error) {
+	return c.ReadDoubleWordsContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadDoubleWordsContext same as ReadDoubleWords, stops waiting for the response when ctx is done
+func (c *Client) ReadDoubleWordsContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]uint32, error) {
+	return wrapRead(c, func() ([]uint32, error) {
+		if err := checkIsDoubleWordMemoryArea(memoryArea); err != nil {
+			return nil, err
+		}
+		if err := checkAddressRange(memoryArea, address, 0, int(readCount)); err != nil {
+			return nil, err
+		}
+		size := doubleWordsPerFrame(c.MaxWordsPerFrame())
+		chunks, err := pipeline(ctx, frameChunks(int(readCount), size), func(ctx context.Context, start int) ([]byte, error) {
+			count := int(readCount) - start
+			if count > size {
+				count = size
+			}
+			addr, err := c.encodeMemoryAddress(memoryArea, address+uint16(start), 0)
+			if err != nil {
+				return nil, err
+			}
+			r, err := c.sendCommandAndCheckResponse(ctx, readCommand(addr, uint16(count)))
+			if err != nil {
+				return nil, err
+			}
+			if len(r.data) != count*4 {
+				return nil, ResponseLengthError{want: count * 4, got: len(r.data)}
+			}
+			return r.data, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return c.bytesToUint32s(bytes.Join(chunks, nil)), nil
+	})
+}
+
+// ReadBytes Reads bytes from the PLC data area
+// note: readCount is count of uint16, not count of byte, so len(return) is 2*readCount
+func (c *Client) ReadBytes(memoryArea byte, address uint16, readCount uint16) ([]byte, error) {
+	return c.ReadBytesContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadBytesContext same as ReadBytes, stops waiting for the response when ctx is done
+func (c *Client) ReadBytesContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]byte, error) {
+	return wrapRead(c, func() ([]byte, error) {
+		return c.readBytes(ctx, memoryArea, address, readCount)
+	})
+}
+
+// ReadString Reads a string from the PLC data area
+// note: readCount is count of uint16, not len of string or count of byte
+func (c *Client) ReadString(memoryArea byte, address uint16, readCount uint16) (string, error) {
+	return c.ReadStringContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadStringContext same as ReadString, stops waiting for the response when ctx is done
+func (c *Client) ReadStringContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) (string, error) {
+	data, err := c.ReadBytesContext(ctx, memoryArea, address, readCount)
+	if err != nil {
+		return "", err
+	}
+	n := bytes.IndexByte(data, 0)
+	if n != -1 {
+		data = data[:n]
+	}
+	return string(data), nil
+}
+
+// ReadBits Reads bits from the PLC data area
+// note: readCount is count of bool, so len(return) is readCount
+func (c *Client) ReadBits(memoryArea byte, address uint16, bitOffset byte, readCount uint16) ([]bool, error) {
+	return c.ReadBitsContext(context.Background(), memoryArea, address, bitOffset, readCount)
+}
+
+// ReadBitsContext same as ReadBits, stops waiting for the response when ctx is done
+func (c *Client) ReadBitsContext(ctx context.Context, memoryArea byte, address uint16, bitOffset byte, readCount uint16) ([]bool, error) {
+	return wrapRead(c, func() ([]bool, error) {
+		return c.readBits(ctx, memoryArea, address, bitOffset, readCount)
+	})
+}
+
+// ReadClock Reads the PLC clock
+func (c *Client) ReadClock() (t *time.Time, err error) {
+	return c.ReadClockContext(context.Background())
+}
+
+// ReadClockContext same as ReadClock, stops waiting for the response when ctx is done
+func (c *Client) ReadClockContext(ctx context.Context) (t *time.Time, err error) {
+	return wrapRead(c, func() (*time.Time, error) {
+		r, e := c.sendCommandAndCheckResponse(ctx, clockReadCommand())
+		if e != nil {
+			return nil, e
+		}
+		return decodeClock(r.data)
+	})
+}
+
+// WriteWords Writes words to the PLC data area
+func (c *Client) WriteWords(memoryArea byte, address uint16, data []uint16) error {
+	return c.WriteWordsContext(context.Background(), memoryArea, address, data)
+}
+
+// WriteWordsContext same as WriteWords, stops waiting for the response when ctx is done
+func (c *Client) WriteWordsContext(ctx context.Context, memoryArea byte, address uint16, data []uint16) error {
+	return c.WriteBytesContext(ctx, memoryArea, address, c.uint16sToBytes(data))
+}
+
+// WriteDoubleWords Writes double words to the PLC data area, only index registers (MemoryAreaIndexRegisterPV) are double word area
+func (c *Client) WriteDoubleWords(memoryArea byte, address uint16, data []uint32) error {
+	return c.WriteDoubleWordsContext(context.Background(), memoryArea, address, data)
+}
+
+// WriteDoubleWordsContext same as WriteDoubleWords, stops waiting for the response when ctx is done
+func (c *Client) WriteDoubleWordsContext(ctx context.Context, memoryArea byte, address uint16, data []uint32) error {
+	if len(data) == 0 {
+		return EmptyWriteRequestError{}
+	}
+	return c.wrapOperate(func() error {
+		if err := checkIsDoubleWordMemoryArea(memoryArea); err != nil {
+			return err
+		}
+		if err := checkAddressRange(memoryArea, address, 0, len(data)); err != nil {
+			return err
+		}
+		size := doubleWordsPerFrame(c.MaxWordsPerFrame())
+		for _, start := range frameChunks(len(data), size) {
+			end := start + size
+			if end > len(data) {
+				end = len(data)
+			}
+			addr, err := c.encodeMemoryAddress(memoryArea, address+uint16(start), 0)
+			if err != nil {
+				return err
+			}
+			command := writeCommand(addr, uint16(end-start), c.uint32sToBytes(data[start:end]))
+			if err = c.checkResponse(c.sendCommand(ctx, command)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WriteBytes Writes bytes array to the PLC data area
+// Example:
+//
+//	WriteBytes(A, 100, []byte{0x01}) will set A100=256  [01 00]
+//	WriteBytes(A, 100, []byte{0x01,0x02}) will set A100=256+2 [01 01]
+//	WriteBytes(A, 100, []byte{0x01,0x02,0x01}) will set A100=256+2, A101=256  [01 01 01 00]
+//
+// Warning:
+//
+//	if len(b) is not even, I append 0 to the end of b, cause low byte of last memory will be set to 0
+//	 A200=1(0x00 0x01), call WriteBytes(A, 100, []byte{0x01}), A200 will be 256(0x01,0x00)
+func (c *Client) WriteBytes(memoryArea byte, address uint16, b []byte) error {
+	return c.WriteBytesContext(context.Background(), memoryArea, address, b)
+}
+
+// WriteBytesContext same as WriteBytes, stops waiting for the response when ctx is done
+func (c *Client) WriteBytesContext(ctx context.Context, memoryArea byte, address uint16, b []byte) error {
+	if len(b) == 0 {
+		return EmptyWriteRequestError{}
+	}
+	if len(b)%2 != 0 {
+		b = append(b, 0)
+	}
+	return c.wrapOperate(func() error {
+		if err := checkIsWordMemoryArea(memoryArea); err != nil {
+			return err
+		}
+		if err := checkAddressRange(memoryArea, address, 0, len(b)/2); err != nil {
+			return err
+		}
+		size := c.MaxWordsPerFrame() * 2
+		for _, start := range frameChunks(len(b), size) {
+			end := start + size
+			if end > len(b) {
+				end = len(b)
+			}
+			addr, err := c.encodeMemoryAddress(memoryArea, address+uint16(start/2), 0)
+			if err != nil {
+				return err
+			}
+			command := writeCommand(addr, uint16((end-start)/2), b[start:end])
+			if err = c.checkResponse(c.sendCommand(ctx, command)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// WriteString Writes a string to the PLC data area
+// Example:
+//
+//	WriteString(A, 100, "12") will set A100=[0x31,0x32]
+//	WriteString(A, 100, "1") will set A100=[0x31,0x00]
+//
+// Warning:
+//
+//	same as WriteBytes, if len([]byte(s)) is not even, I append 0 to the end of b, cause low byte of last memory will be set to 0
+func (c *Client) WriteString(memoryArea byte, address uint16, s string) error {
+	return c.WriteStringContext(context.Background(), memoryArea, address, s)
+}
+
+// WriteStringContext same as WriteString, stops waiting for the response when ctx is done
+func (c *Client) WriteStringContext(ctx context.Context, memoryArea byte, address uint16, s string) error {
+	return c.WriteBytesContext(ctx, memoryArea, address, []byte(s))
+}
+
+// WriteBits Writes bits to the PLC data area
+// Example:
+//
+//	WriteBits(A, 100, 0, []bool{true,true}) will set A100=256  [00 03]
+//	WriteBits(A, 100, 0, []bool{true,true,true,true,true,true,true,true,true,true,true,true,true,true,true,true,true}) will set A100=65535,A101=1  [FF FF 00 01]
+func (c *Client) WriteBits(memoryArea byte, address uint16, bitOffset byte, data []bool) error {
+	return c.WriteBitsContext(context.Background(), memoryArea, address, bitOffset, data)
+}
+
+// WriteBitsContext same as WriteBits, stops waiting for the response when ctx is done
+func (c *Client) WriteBitsContext(ctx context.Context, memoryArea byte, address uint16, bitOffset byte, data []bool) error {
+	return c.wrapOperate(func() error {
+		if err := checkIsBitMemoryArea(memoryArea); err != nil {
+			return err
+		}
+		if err := checkIsWritableMemoryArea(memoryArea); err != nil {
+			return err
+		}
+		bts := make([]byte, 0, len(data))
+		for i := 0; i < len(data); i++ {
+			var d byte
+			if data[i] {
+				d = 0x01
+			}
+			bts = append(bts, d)
+		}
+		if err := checkAddressRange(memoryArea, address, bitOffset, len(bts)); err != nil {
+			return err
+		}
+		size := c.MaxWordsPerFrame() * 2
+		for _, start := range frameChunks(len(bts), size) {
+			end := start + size
+			if end > len(bts) {
+				end = len(bts)
+			}
+			a, b := bitChunkAddress(memoryArea, address, bitOffset, start)
+			addr, err := c.encodeMemoryAddress(memoryArea, a, b)
+			if err != nil {
+				return err
+			}
+			command := writeCommand(addr, uint16(end-start), bts[start:end])
+			if err = c.checkResponse(c.sendCommand(ctx, command)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetBit Sets a bit in the PLC data area
+func (c *Client) SetBit(memoryArea byte, address uint16, bitOffset byte) error {
+	return c.SetBitContext(context.Background(), memoryArea, address, bitOffset)
+}
+
+// SetBitContext same as SetBit, stops waiting for the response when ctx is done
+func (c *Client) SetBitContext(ctx context.Context, memoryArea byte, address uint16, bitOffset byte) error {
+	return c.bitTwiddle(ctx, memoryArea, address, bitOffset, 0x01)
+}
+
+// ResetBit Resets a bit in the PLC data area
+// Example:
+//
+//	ResetBit(A, 100, 0) will set A100.0=0  [00 01] -> [00 00]
+//	ResetBit(A, 100, 16) will set A101.0=0  [00 00 00 01] -> [00 00 00 00]
+func (c *Client) ResetBit(memoryArea byte, address uint16, bitOffset byte) error {
+	return c.ResetBitContext(context.Background(), memoryArea, address, bitOffset)
+}
+
+// ResetBitContext same as ResetBit, stops waiting for the response when ctx is done
+func (c *Client) ResetBitContext(ctx context.Context, memoryArea byte, address uint16, bitOffset byte) error {
+	return c.bitTwiddle(ctx, memoryArea, address, bitOffset, 0x00)
+}
+
+// ToggleBit Toggles a bit in the PLC data area
+func (c *Client) ToggleBit(memoryArea byte, address uint16, bitOffset byte) error {
+	return c.ToggleBitContext(context.Background(), memoryArea, address, bitOffset)
+}
+
+// ToggleBitContext same as ToggleBit, stops waiting for the response when ctx is done
+func (c *Client) ToggleBitContext(ctx context.Context, memoryArea byte, address uint16, bitOffset byte) error {
+	return c.wrapOperate(func() error {
+		b, err := c.readBits(ctx, memoryArea, address, bitOffset, 1)
+		if err != nil {
+			return err
+		}
+		var t byte
+		if b[0] {
+			t = 0x01
+		}
+		return c._bitTwiddle(ctx, memoryArea, address, bitOffset, t)
+	})
+}
+
+// SetByteOrder
+// Set byte order of words, see SetWordOrder for 32-bit and 64-bit values
+// Default value: binary.BigEndian
+func (c *Client) SetByteOrder(o binary.ByteOrder) {
+	if o != nil {
+		c.byteOrder.Store(byteOrderValue{o})
+	}
+}
+
+// byteOrderValue keeps the type stored in atomic.Value the same, it panics when binary.LittleEndian is stored after binary.BigEndian
+type byteOrderValue struct {
+	binary.ByteOrder
+}
+
+// SetDialect
+// Set memory area codes and address layout of the PLC, memory areas are still given with CS/CJ mode constants
+// Default value: DialectCSCJ
+func (c *Client) SetDialect(d Dialect) {
+	c.dialect.Store(int32(d))
+}
+
+// Dialect returns memory area codes and address layout of the PLC
+func (c *Client) Dialect() Dialect {
+	return Dialect(c.dialect.Load())
+}
+
+// SetMaxWordsPerFrame
+// Set max words read or written by one frame, larger reads and writes are split into several frames.
+// frames of a read are sent without waiting for each other and their results are put together in order,
+// frames of a write are sent one by one and stop at the first error, so a split write is not atomic:
+// frames before the failed one are written.
+// bits are read and written 2*words per frame
+// the limit of a known CPU unit model can be set by SetMaxWordsPerFrameOfCPUUnit
+// Default value: DefaultMaxWordsPerFrame
+func (c *Client) SetMaxWordsPerFrame(words uint16) {
+	if words > 0 {
+		c.maxWordsPerFrame.Store(int32(words))
+	}
+}
+
+// MaxWordsPerFrame returns max words read or written by one frame
+func (c *Client) MaxWordsPerFrame() int {
+	return int(c.maxWordsPerFrame.Load())
+}
+
+// SetTimeoutMs
+// Set response timeout duration (ms).
+// Default value: 20ms.
+// A timeout of zero can be used to block indefinitely.
+func (c *Client) SetTimeoutMs(t uint) {
+	c.responseTimeout.Store(int64(time.Duration(t) * time.Millisecond))
+}
+
+// SetReadGoroutineNum
+// Note: won't stop running goroutine
+func (c *Client) SetReadGoroutineNum(count uint8) {
+	if count > 0 {
+		c.readGoroutineNum.Store(int32(count))
+	}
+}
+
+// Close Closes an Omron FINS connection
+func (c *Client) Close() {
+	c.sf.do(c.wrapClose)
+}
+
+// SetIgnoreErrorCodes
+// Set ignore error codes
+func (c *Client) SetIgnoreErrorCodes(codes []uint16) {
+	mp := map[uint16]struct{}{}
+	for _, code := range codes {
+		mp[code] = struct{}{}
+	}
+	c.ignoreErrorCode.Store(mp)
+}
+
+// ============== private ==============
+func (c *Client) initTransportAndStartReadLoop() error {
+	if c.closing.Load() {
+		return ClientClosingError{}
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.transport != nil {
+		return nil
+	}
+	if c.dial == nil {
+		return &net.OpError{Op: "dial", Err: errors.New("missing dial func")}
+	}
+	t, er := c.dial()
+	if er != nil {
+		return er
+	}
+	if t == nil {
+		return &net.OpError{Op: "dial", Err: errors.New("dail return nil transport and nil error")}
+	}
+	c.setTransportAndCtx(t)
+
+	ctx := c.ctx
+	rn := int(c.readGoroutineNum.Load())
+	c.wg.Add(rn)
+	for i := 0; i < rn; i++ {
+		go func() {
+			defer c.wg.Done()
+			c.readLoop(ctx, t)
+		}()
+	}
+
+	return nil
+}
+
+func (c *Client) readLoop(ctx context.Context, t Transport) {
+	var buf = make([]byte, udpPacketMaxSize)
+	done := ctx.Done()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			n, err := t.Receive(buf)
+			if errors.Is(err, io.EOF) || errors.As(err, new(TransportBrokenError)) {
+				// the PLC closed the transport or it is broken, drop it and dial again on next operation
+				c.printFinsPacketError("fins client: transport closed: %s", err)
+				c.dropTransport(t)
+				return
+			}
+			if err != nil || n < minResponsePacketSize {
+				c.handleReadError(ctx, n, err, buf)
+				continue
+			}
+
+			respPacket := make([]byte, n)
+			copy(respPacket, buf)
+			c.printPacket("read", respPacket)
+			c.sendToSpecificRespChan(ctx, decodeResponse(respPacket))
+		}
+	}
+}
+
+func (c *Client) sendToSpecificRespChan(ctx context.Context, ans *response) {
+	ch := c.resp.getW(ans.header.serviceID)
+	if ch == nil {
+		c.printFinsPacketError("fins client: no resp chan for sid %d. maybe receive goroutine wait timeout", ans.header.serviceID)
+		return
+	}
+
+	timeout := time.Duration(c.responseTimeout.Load())
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case ch <- ans:
+	case <-ctx.Done():
+		c.printFinsPacketError("fins client: failed to send resp to chan resp. ctx.Done()")
+	case <-timer.C:
+		c.printFinsPacketError("wait until timeout %s. still no goroutine to receive resp", timeout)
+	}
+}
+
+func (c *Client) handleReadError(ctx context.Context, n int, err error, buf []byte) {
+	if errors.Is(err, net.ErrClosed) {
+		return
+	}
+	msg := "fins client: failed to read fins response packet: "
+	if n < minResponsePacketSize && n > 0 {
+		c.printFinsPacketError(msg+"MinResponsePacketSize is %d bytes, got %d bytes: % X", minResponsePacketSize, n, buf[:n])
+	} else if n <= 0 {
+		c.printFinsPacketError(msg+"Receive return %d", n)
+	}
+	if err != nil {
+		c.printFinsPacketError("fins client: failed to Receive: " + err.Error())
+	}
+	waitMoment(ctx, time.Millisecond*100)
+	return
+}
+
+func (c *Client) createRequest(command []byte) (byte, []byte) {
+	sid := c.sid.increment()
+	c.m.Lock()
+	header := defaultCommandHeader(c.local, c.remote, sid)
+	c.m.Unlock()
+	bts := encodeHeader(header)
+	bts = append(bts, command...)
+	return sid, bts
+}
+
+// sendCommand sends command and waits for its response until timeout, ctx done or Client closed
+func (c *Client) sendCommand(ctx context.Context, command []byte) (*response, error) {
+	t, tctx := c.getTransportAndCtx()
+	if t == nil {
+		return nil, ClientClosedError{}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sid, reqPacket := c.createRequest(command)
+
+	respCh := make(chan *response)
+	c.resp.set(sid, respCh)
+	defer func() {
+		c.resp.set(sid, nil)
+	}()
+
+	c.printPacket("write", reqPacket)
+	err := t.Send(reqPacket)
+	if err != nil {
+		return nil, err
+	}
+	var timeoutChan <-chan time.Time
+	d := time.Duration(c.responseTimeout.Load())
+	if d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeoutChan = timer.C
+	}
+	select {
+	case <-tctx.Done():
+		return nil, ClientClosedError{}
+	case <-ctx.Done(): // deferred c.resp.set release the sid slot right away
+		return nil, ctx.Err()
+	case respV := <-respCh:
+		return respV, nil
+	case <-timeoutChan:
+		return nil, ResponseTimeoutError{d} // can not actually happen if d == 0
+	}
+}
+
+func (c *Client) sendCommandAndCheckResponse(ctx context.Context, command []byte) (*response, error) {
+	resp, err := c.sendCommand(ctx, command)
+	if err != nil {
+		return nil, err
+	}
+	er := c.checkResponse(resp, err)
+	if er != nil {
+		return nil, er
+	}
+	return resp, nil
+}
+
+func (c *Client) bitTwiddle(ctx context.Context, memoryArea byte, address uint16, bitOffset byte, value byte) error {
+	return c.wrapOperate(func() error {
+		if err := checkIsBitMemoryArea(memoryArea); err != nil {
+			return err
+		}
+		if err := checkIsWritableMemoryArea(memoryArea); err != nil {
+			return err
+		}
+		return c._bitTwiddle(ctx, memoryArea, address, bitOffset, value)
+	})
+}
+
+func (c *Client) _bitTwiddle(ctx context.Context, memoryArea byte, address uint16, bitOffset byte, value byte) error {
+	mem, err := c.encodeMemoryAddress(memoryArea, address, bitOffset)
+	if err != nil {
+		return err
+	}
+	command := writeCommand(mem, 1, []byte{value})
+	return c.checkResponse(c.sendCommand(ctx, command))
+}
+
+func (c *Client) readBits(ctx context.Context, memoryArea byte, address uint16, bitOffset byte, readCount uint16) ([]bool, error) {
+	if err := checkIsBitMemoryArea(memoryArea); err != nil {
+		return nil, err
+	}
+	if err := checkAddressRange(memoryArea, address, bitOffset, int(readCount)); err != nil {
+		return nil, err
+	}
+	size := c.MaxWordsPerFrame() * 2
+	chunks, err := pipeline(ctx, frameChunks(int(readCount), size), func(ctx context.Context, start int) ([]byte, error) {
+		count := int(readCount) - start
+		if count > size {
+			count = size
+		}
+		a, b := bitChunkAddress(memoryArea, address, bitOffset, start)
+		addr, err := c.encodeMemoryAddress(memoryArea, a, b)
+		if err != nil {
+			return nil, err
+		}
+		r, err := c.sendCommandAndCheckResponse(ctx, readCommand(addr, uint16(count)))
+		if err != nil {
+			return nil, err
+		}
+		if len(r.data) != count {
+			return nil, ResponseLengthError{want: count, got: len(r.data)}
+		}
+		return r.data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]bool, 0, readCount)
+	for _, data := range chunks {
+		for _, d := range data {
+			result = append(result, d&0x01 > 0)
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) readBytes(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]byte, error) {
+	if err := checkIsWordMemoryArea(memoryArea); err != nil {
+		return nil, err
+	}
+	if err := checkAddressRange(memoryArea, address, 0, int(readCount)); err != nil {
+		return nil, err
+	}
+	size := c.MaxWordsPerFrame()
+	chunks, err := pipeline(ctx, frameChunks(int(readCount), size), func(ctx context.Context, start int) ([]byte, error) {
+		count := int(readCount) - start
+		if count > size {
+			count = size
+		}
+		addr, err := c.encodeMemoryAddress(memoryArea, address+uint16(start), 0)
+		if err != nil {
+			return nil, err
+		}
+		r, err := c.sendCommandAndCheckResponse(ctx, readCommand(addr, uint16(count)))
+		if err != nil {
+			return nil, err
+		}
+		if len(r.data) != count*2 {
+			return nil, ResponseLengthError{want: count * 2, got: len(r.data)}
+		}
+		return r.data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Join(chunks, nil), nil
+}
+
+// encodeMemoryAddress memory address in the dialect of the PLC
+func (c *Client) encodeMemoryAddress(memoryArea byte, address uint16, bitOffset byte) (memoryAddress, error) {
+	return c.Dialect().encodeMemoryAddress(memAddrWithBitOffset(memoryArea, address, bitOffset))
+}
+
+func (c *Client) wrapOperate(do func() error) error {
+	c.wg.Add(1)
+	defer c.wg.Done()
+	err := c.initTransportAndStartReadLoop()
+	if err != nil {
+		return err
+	}
+	return do()
+}
+
+func (c *Client) getTransportAndCtx() (Transport, context.Context) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.transport, c.ctx
+}
+
+func (c *Client) setTransportAndCtx(t Transport) {
+	c.transport = t
+	if t == nil {
+		c.ctx, c.cancel = context.Background(), func() {}
+	} else {
+		c.local, c.remote = t.Addresses()
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+	}
+}
+
+// dropTransport closes t if it is still the current one, next operation will dial again
+func (c *Client) dropTransport(t Transport) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.transport != t {
+		return
+	}
+	c.cancel()
+	c.transport.Close()
+	c.setTransportAndCtx(nil)
+}
+
+func (c *Client) closeTransport() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.transport == nil {
+		return
+	}
+	c.cancel()
+	c.transport.Close()
+}
+
+func (c *Client) wrapClose() {
+	c.closing.Store(true)
+	defer c.closing.Store(false)
+	c.closeTransport()
+	c.wg.Wait()
+	// if c.wg.Wait() return, means no goroutine use this client
+	// and since c.closing is true,  no new goroutine can use this client
+	// so setTransportAndCtx can call without protection of c.m
+	c.setTransportAndCtx(nil)
+}
+
+func (c *Client) uint16sToBytes(us []uint16) []byte {
+	bts := make([]byte, 2*len(us), 2*len(us))
+	order, ok := c.byteOrder.Load().(binary.ByteOrder)
+	if !ok {
+		order = binary.BigEndian
+	}
+	for i := 0; i < len(us); i++ {
+		order.PutUint16(bts[i*2:i*2+2], us[i])
+	}
+	return bts
+}
+
+func (c *Client) bytesToUint16s(bs []byte) []uint16 {
+	order, ok := c.byteOrder.Load().(binary.ByteOrder)
+	if !ok {
+		order = binary.BigEndian
+	}
+
+	data := make([]uint16, len(bs)/2)
+	for i := 0; i < len(bs)/2; i++ {
+		data[i] = order.Uint16(bs[i*2 : i*2+2])
+	}
+	return data
+}
+
+func (c *Client) uint32sToBytes(us []uint32) []byte {
+	order, ok := c.byteOrder.Load().(binary.ByteOrder)
+	if !ok {
+		order = binary.BigEndian
+	}
+	bts := make([]byte, 4*len(us))
+	for i := 0; i < len(us); i++ {
+		order.PutUint32(bts[i*4:i*4+4], us[i])
+	}
+	return bts
+}
+
+func (c *Client) bytesToUint32s(bs []byte) []uint32 {
+	order, ok := c.byteOrder.Load().(binary.ByteOrder)
+	if !ok {
+		order = binary.BigEndian
+	}
+	data := make([]uint32, len(bs)/4)
+	for i := 0; i < len(bs)/4; i++ {
+		data[i] = order.Uint32(bs[i*4 : i*4+4])
+	}
+	return data
+}
+
+func (c *Client) checkResponse(r *response, err error) error {
+	if err != nil {
+		return err
+	}
+	if r.endCode == EndCodeNormalCompletion {
+		return nil
+	}
+	m, _ := c.ignoreErrorCode.Load().(map[uint16]struct{})
+	if _, ok := m[r.endCode]; ok {
+		return nil
+	}
+	return newEndCodeError(r)
+}
+
+func wrapRead[T any](c *Client, do func() (T, error)) (result T, err error) {
+	c.wg.Add(1)
+	defer c.wg.Done()
+	if err = c.initTransportAndStartReadLoop(); err != nil {
+		return result, err
+	}
+	return do()
+}
+
+func decodeClock(data []byte) (*time.Time, error) {
+	if len(data) < 6 {
+		return nil, fmt.Errorf("failed to decode colck: data length should be 6, got: %d", len(data))
+	}
+	year, err := decodeBCD(data[0:1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode year from %X: %w", data[0:1], err)
+	}
+	if year < 50 {
+		year += 2000
+	} else {
+		year += 1900
+	}
+	month, err := decodeBCD(data[1:2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode month from %X: %w", data[1], err)
+	}
+	day, err := decodeBCD(data[2:3])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode day from %X: %w", data[2], err)
+	}
+	hour, err := decodeBCD(data[3:4])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode hour from %X: %w", data[3], err)
+	}
+	minute, err := decodeBCD(data[4:5])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode minute from %X: %w", data[4], err)
+	}
+	second, err := decodeBCD(data[5:6])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode second from % X: %w", data[5:6], err)
+	}
+	tt := time.Date(int(year), time.Month(month), int(day), int(hour), int(minute), int(second), 0, time.Local)
+	return &tt, nil
+}
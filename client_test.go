@@ -0,0 +1,241 @@
+package fins
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryTransport in-memory Transport, every frame sent is answered by handler
+type memoryTransport struct {
+	handler func(request) response
+	ch      chan []byte
+	once    sync.Once
+	done    chan struct{}
+}
+
+func newMemoryTransport(handler func(request) response) *memoryTransport {
+	return &memoryTransport{handler: handler, ch: make(chan []byte, 16), done: make(chan struct{})}
+}
+
+func (t *memoryTransport) Send(frame []byte) error {
+	select {
+	case <-t.done:
+		return net.ErrClosed
+	case t.ch <- encodeResponse(t.handler(decodeRequest(frame))):
+		return nil
+	}
+}
+
+func (t *memoryTransport) Receive(buf []byte) (int, error) {
+	select {
+	case <-t.done:
+		return 0, net.ErrClosed
+	case frame := <-t.ch:
+		return copy(buf, frame), nil
+	}
+}
+
+func (t *memoryTransport) Close() error {
+	t.once.Do(func() { close(t.done) })
+	return nil
+}
+
+func (t *memoryTransport) Addresses() (local, remote DeviceAddress) {
+	return NewDeviceAddress(0, 1, 0), NewDeviceAddress(0, 2, 0)
+}
+
+func TestClient_memoryTransport(t *testing.T) {
+	var dialCount int
+	c := NewClient(func() (Transport, error) {
+		dialCount++
+		return newMemoryTransport(func(r request) response {
+			assert.Equal(t, NewDeviceAddress(0, 1, 0), r.header.src)
+			assert.Equal(t, NewDeviceAddress(0, 2, 0), r.header.dst)
+			data := []byte{0x24, 0x10, 0x17, 0x08, 0x30, 0x05}
+			return response{defaultResponseHeader(r.header), r.commandCode, EndCodeNormalCompletion, data}
+		}), nil
+	})
+	defer c.Close()
+
+	clock, err := c.ReadClock()
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-10-17 08:30:05", clock.Format("2006-01-02 15:04:05"))
+
+	c.Close()
+	_, err = c.ReadClock()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, dialCount, "should dial again after Close")
+}
+
+// silentTransport never answers
+type silentTransport struct {
+	memoryTransport
+}
+
+func (t *silentTransport) Send([]byte) error {
+	return nil
+}
+
+func TestClient_ReadWordsContext(t *testing.T) {
+	c := NewClient(func() (Transport, error) {
+		return &silentTransport{*newMemoryTransport(nil)}, nil
+	})
+	defer c.Close()
+	c.SetTimeoutMs(0) // block until ctx done
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.ReadWordsContext(ctx, MemoryAreaDMWord, 100, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, c.resp.getR(c.sid.load()), "sid slot should be released")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	err = c.WriteWordsContext(ctx, MemoryAreaDMWord, 100, []uint16{1})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// newSimulatorClient returns a Client talking to an in-memory simulator
+func newSimulatorClient() (*Client, *simulator) {
+	s := &simulator{}
+	s.initMemory()
+	c := NewClient(func() (Transport, error) {
+		return newMemoryTransport(s.handler), nil
+	})
+	return c, s
+}
+
+func TestClient_splitReadWrite(t *testing.T) {
+	c, s, sent := newCountingSimulatorClient()
+	defer c.Close()
+
+	words := make([]uint16, 2500)
+	for i := range words {
+		words[i] = uint16(i)
+	}
+	assert.Nil(t, c.WriteWords(MemoryAreaDMWord, 100, words))
+	// 996 + 996 + 508 words
+	assert.Len(t, sent(), 3)
+	got, err := c.ReadWords(MemoryAreaDMWord, 100, 2500)
+	assert.Nil(t, err)
+	assert.Equal(t, words, got)
+	assert.Len(t, sent(), 3)
+
+	bits := make([]bool, 4000)
+	for i := range bits {
+		bits[i] = i%3 == 0
+	}
+	assert.Nil(t, c.WriteBits(MemoryAreaWRBit, 1, 5, bits))
+	// 1992 + 1992 + 16 bits, the second frame starts at W125.05
+	assert.Len(t, sent(), 3)
+	gotBits, err := c.ReadBits(MemoryAreaWRBit, 1, 5, 4000)
+	assert.Nil(t, err)
+	assert.Equal(t, bits, gotBits)
+	assert.Len(t, sent(), 3)
+	w, err := c.ReadWords(MemoryAreaWRWord, 125, 1)
+	assert.Nil(t, err)
+	// W125.00 is bit 1979, every third bit from W125.01 is set
+	assert.Equal(t, []uint16{0x2492}, w)
+	sent()
+
+	// timer completion flags take one address each
+	flags := make([]bool, 2100)
+	flags[0], flags[1992], flags[2099] = true, true, true
+	assert.Nil(t, c.WriteBits(MemoryAreaTimerCounterCompletionFlag, 10, 0, flags))
+	assert.Len(t, sent(), 2)
+	gotFlags, err := c.ReadBits(MemoryAreaTimerCounterCompletionFlag, 10, 0, 2100)
+	assert.Nil(t, err)
+	assert.Equal(t, flags, gotFlags)
+	b, err := c.ReadBits(MemoryAreaTimerCounterCompletionFlag, 2002, 0, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true}, b)
+	sent()
+
+	c.SetMaxWordsPerFrame(0) // ignored
+	c.SetMaxWordsPerFrame(100)
+	assert.Equal(t, 100, c.MaxWordsPerFrame())
+	got, err = c.ReadWords(MemoryAreaDMWord, 100, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, words[:1000], got)
+	assert.Len(t, sent(), 10)
+
+	// simulator rejects frames over its limit
+	s.SetMaxWordsPerFrame(50)
+	_, err = c.ReadWords(MemoryAreaDMWord, 100, 100)
+	assert.Equal(t, EndCodeError{EndCodeResponseTooBig}, err)
+	assert.Equal(t, EndCodeError{EndCodeCommandTooLong}, c.WriteWords(MemoryAreaDMWord, 100, words[:100]))
+	_, err = c.ReadBits(MemoryAreaWRBit, 0, 0, 101)
+	assert.Equal(t, EndCodeError{EndCodeResponseTooBig}, err)
+	c.SetMaxWordsPerFrame(50)
+	got, err = c.ReadWords(MemoryAreaDMWord, 100, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, words[:100], got)
+}
+
+func TestClient_splitWriteStopsAtError(t *testing.T) {
+	c, _, sent := newCountingSimulatorClient()
+	defer c.Close()
+
+	// D31768-D32763 is written, D32764 is out of range and D33760 is not sent
+	err := c.WriteWords(MemoryAreaDMWord, 31768, make([]uint16, 2500))
+	assert.Equal(t, EndCodeError{EndCodeAddressRangeExceeded}, err)
+	assert.Len(t, sent(), 2)
+
+	assert.Equal(t, AddressOverflowError{MemoryAreaDMWord, 0xffff, 2}, c.WriteWords(MemoryAreaDMWord, 0xffff, []uint16{1, 2}))
+	_, err = c.ReadWords(MemoryAreaDMWord, 0xff00, 0x100+1)
+	assert.Equal(t, AddressOverflowError{MemoryAreaDMWord, 0xff00, 0x101}, err)
+	assert.Equal(t, AddressOverflowError{MemoryAreaWRBit, 0xffff, 2}, c.WriteBits(MemoryAreaWRBit, 0xffff, 15, []bool{true, true}))
+	_, err = c.ReadBits(MemoryAreaTimerCounterCompletionFlag, 0xfffe, 0, 3)
+	assert.Equal(t, AddressOverflowError{MemoryAreaTimerCounterCompletionFlag, 0xfffe, 3}, err)
+	assert.Empty(t, sent(), "overflowing ranges are rejected before sending")
+}
+
+func TestClient_splitDoubleWords(t *testing.T) {
+	c, s, sent := newCountingSimulatorClient()
+	defer c.Close()
+
+	// 4 words are 2 index registers a frame
+	s.SetMaxWordsPerFrame(4)
+	c.SetMaxWordsPerFrame(4)
+	values := []uint32{1, 2, 3, 4, 0x12345678}
+	assert.Nil(t, c.WriteDoubleWords(MemoryAreaIndexRegisterPV, 10, values))
+	assert.Len(t, sent(), 3)
+	got, err := c.ReadDoubleWords(MemoryAreaIndexRegisterPV, 10, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, values, got)
+	assert.Len(t, sent(), 3)
+
+	c.SetMaxWordsPerFrame(1)
+	got, err = c.ReadDoubleWords(MemoryAreaIndexRegisterPV, 14, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{0x12345678}, got)
+	sent()
+
+	_, err = c.ReadDoubleWords(MemoryAreaIndexRegisterPV, 0xfffe, 3)
+	assert.Equal(t, AddressOverflowError{MemoryAreaIndexRegisterPV, 0xfffe, 3}, err)
+	assert.Equal(t, AddressOverflowError{MemoryAreaIndexRegisterPV, 0xffff, 2},
+		c.WriteDoubleWords(MemoryAreaIndexRegisterPV, 0xffff, []uint32{1, 2}))
+	assert.Empty(t, sent(), "overflowing ranges are rejected before sending")
+}
+
+func TestClient_SetByteOrder(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	c.SetByteOrder(binary.LittleEndian)
+	assert.Nil(t, c.WriteWords(MemoryAreaDMWord, 0, []uint16{0x1234}))
+	b, err := c.ReadBytes(MemoryAreaDMWord, 0, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x34, 0x12}, b)
+
+	c.SetByteOrder(binary.BigEndian)
+	words, err := c.ReadWords(MemoryAreaDMWord, 0, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0x3412}, words)
+}
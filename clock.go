@@ -0,0 +1,74 @@
+package fins
+
+import (
+	"context"
+	"time"
+)
+
+// WriteClock Sets the PLC clock
+// t is converted to local time like ReadClock returns, sub-second part is dropped
+// the PLC clock supports year 1950 to 2049
+func (c *Client) WriteClock(t time.Time) error {
+	return c.WriteClockContext(context.Background(), t)
+}
+
+// WriteClockContext same as WriteClock, stops waiting for the response when ctx is done
+func (c *Client) WriteClockContext(ctx context.Context, t time.Time) error {
+	clock, err := encodeClock(t)
+	if err != nil {
+		return err
+	}
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, clockWriteCommand(clock)))
+	})
+}
+
+// SyncClock Sets the PLC clock to the host clock if they differ more than maxDrift
+// returns drift of the PLC clock before syncing, positive if the PLC clock is ahead
+// half of the round trip time is taken as transmission delay, and the clock is written
+// at a whole second boundary because the PLC clock has no sub-second part.
+// it may take up to one second
+func (c *Client) SyncClock(maxDrift time.Duration) (time.Duration, error) {
+	return c.SyncClockContext(context.Background(), maxDrift)
+}
+
+// SyncClockContext same as SyncClock, stops waiting when ctx is done
+func (c *Client) SyncClockContext(ctx context.Context, maxDrift time.Duration) (time.Duration, error) {
+	start := time.Now()
+	plc, err := c.ReadClockContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	// the PLC clock is truncated to seconds, so it is half a second behind on average
+	drift := plc.Add(time.Second / 2).Sub(start.Add(rtt / 2))
+	if drift <= maxDrift && drift >= -maxDrift {
+		return drift, nil
+	}
+
+	// the write takes half a round trip to reach the PLC, which starts the new second right away
+	next := time.Now().Add(rtt / 2).Truncate(time.Second).Add(time.Second)
+	timer := time.NewTimer(time.Until(next.Add(-rtt / 2)))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return drift, ctx.Err()
+	case <-timer.C:
+	}
+	return drift, c.WriteClockContext(ctx, next)
+}
+
+// encodeClock encodes t as clock data: year, month, day, hour, minute, second and day of week in BCD
+func encodeClock(t time.Time) ([]byte, error) {
+	t = t.In(time.Local)
+	if t.Year() < 1950 || t.Year() > 2049 {
+		return nil, ClockOutOfRangeError{t}
+	}
+	fields := []int{t.Year() % 100, int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(), int(t.Weekday())}
+	clock := make([]byte, len(fields))
+	for i, v := range fields {
+		bcd, _ := encodeBCDFixed(uint64(v), 1)
+		clock[i] = bcd[0]
+	}
+	return clock, nil
+}
@@ -0,0 +1,63 @@
+package fins
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_WriteClock(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	want := time.Date(2031, time.March, 9, 23, 59, 58, 0, time.Local)
+	assert.Nil(t, c.WriteClock(want))
+	clock, err := c.ReadClock()
+	assert.Nil(t, err)
+	assert.WithinDuration(t, want, *clock, 2*time.Second)
+
+	err = c.WriteClock(time.Date(2050, time.January, 1, 0, 0, 0, 0, time.Local))
+	assert.IsType(t, ClockOutOfRangeError{}, err)
+}
+
+func TestClient_SyncClock(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	drift, err := c.SyncClock(2 * time.Second)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, drift.Seconds(), 1)
+
+	s.clockOffset = -time.Hour
+	drift, err = c.SyncClock(2 * time.Second)
+	assert.Nil(t, err)
+	assert.InDelta(t, -time.Hour.Seconds(), drift.Seconds(), 1)
+	assert.InDelta(t, 0, s.clockOffset.Seconds(), 0.1)
+}
+
+func Test_encodeClock(t *testing.T) {
+	// 2024-10-17 is Thursday
+	clock, err := encodeClock(time.Date(2024, time.October, 17, 8, 30, 5, 999, time.Local))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x24, 0x10, 0x17, 0x08, 0x30, 0x05, 0x04}, clock)
+
+	clock, err = encodeClock(time.Date(1999, time.December, 31, 23, 59, 59, 0, time.Local))
+	assert.Nil(t, err)
+	decoded, err := decodeClock(clock)
+	assert.Nil(t, err)
+	assert.Equal(t, "1999-12-31 23:59:59", decoded.Format("2006-01-02 15:04:05"))
+}
+
+func Test_encodeBCDFixed(t *testing.T) {
+	bcd, err := encodeBCDFixed(1234, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x12, 0x34}, bcd)
+
+	bcd, err = encodeBCDFixed(7, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x00, 0x07}, bcd)
+
+	_, err = encodeBCDFixed(12345, 2)
+	assert.Equal(t, BCDOverflowError{}, err)
+}
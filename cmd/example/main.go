@@ -1,9 +1,7 @@
 package main
 
 import (
-	"encoding/binary"
 	"fmt"
-	"math"
 	"time"
 
 	"github.com/xiaotushaoxia/fins"
@@ -44,19 +42,16 @@ func main() {
 	}
 	fmt.Println(z)
 	// output: [1 65535 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0]
-	buf := make([]byte, 8, 8)
-	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(15.6))
-	err = c.WriteBytes(fins.MemoryAreaDMWord, 10, buf)
+	err = c.WriteFloat64s(fins.MemoryAreaDMWord, 10, []float64{15.6})
 	if err != nil {
 		panic(err)
 	}
 
-	b, err := c.ReadBytes(fins.MemoryAreaDMWord, 10, 4)
+	floats, err := c.ReadFloat64s(fins.MemoryAreaDMWord, 10, 1)
 	if err != nil {
 		panic(err)
 	}
-	floatRes := math.Float64frombits(binary.LittleEndian.Uint64(b))
-	fmt.Println("Float result:", floatRes)
+	fmt.Println("Float result:", floats[0])
 	// output: Float result: 15.6
 
 	err = c.WriteString(fins.MemoryAreaDMWord, 10000, "teststring")
@@ -81,9 +76,19 @@ func main() {
 	c.ResetBit(fins.MemoryAreaDMBit, 24003, 0)
 	c.ToggleBit(fins.MemoryAreaDMBit, 24003, 2)
 
+	now := time.Now()
+	fmt.Printf("Setting PLC time to: %s\n", now.Format(time.RFC3339))
+	if err = c.WriteClock(now); err != nil {
+		panic(err)
+	}
+
 	for {
 		time.Sleep(time.Second * 5)
+		drift, err := c.SyncClock(time.Second)
+		if err != nil {
+			panic(err)
+		}
 		t, _ := c.ReadClock()
-		fmt.Printf("Setting PLC time to: %s\n", t.Format(time.RFC3339))
+		fmt.Printf("PLC time: %s, drift before sync: %s\n", t.Format(time.RFC3339), drift)
 	}
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -65,27 +66,22 @@ func exec(ss []string) {
 		return
 	}
 
-	rw, mt, dt, addr, count, values, exit := processInputCmdAndShowHelp(ss)
+	rw, dt, addr, count, values, exit := processInputCmdAndShowHelp(ss)
 	if exit {
 		return
 	}
-	ma, ok := getMemoryArea(mt, dt)
-	if !ok {
-		fmt.Println("invalid memory type and data type: ", mt, dt)
-		return
-	}
 	if rw == "r" {
 		var result any
 		var err error
 		switch dt {
 		case "b":
-			result, err = client.ReadBits(ma, addr, 0, count)
+			result, err = client.ReadBitsAt(context.Background(), addr, count)
 		case "B":
-			result, err = client.ReadBytes(ma, addr, count)
+			result, err = client.ReadBytes(addr.MemoryArea, addr.Address, count)
 		case "s":
-			result, err = client.ReadString(ma, addr, count)
+			result, err = client.ReadString(addr.MemoryArea, addr.Address, count)
 		case "w":
-			result, err = client.ReadWords(ma, addr, count)
+			result, err = client.ReadWordsAt(context.Background(), addr, count)
 		}
 		if err != nil {
 			fmt.Println("read error: " + err.Error())
@@ -98,13 +94,13 @@ func exec(ss []string) {
 	var err error
 	switch dt {
 	case "b":
-		err = client.WriteBits(ma, addr, 0, values.([]bool))
+		err = client.WriteBitsAt(context.Background(), addr, values.([]bool))
 	case "B":
-		err = client.WriteBytes(ma, addr, values.([]byte))
+		err = client.WriteBytes(addr.MemoryArea, addr.Address, values.([]byte))
 	case "s":
-		err = client.WriteString(ma, addr, values.(string))
+		err = client.WriteString(addr.MemoryArea, addr.Address, values.(string))
 	case "w":
-		err = client.WriteWords(ma, addr, values.([]uint16))
+		err = client.WriteWordsAt(context.Background(), addr, values.([]uint16))
 	}
 	if err != nil {
 		fmt.Println("write error: " + err.Error())
@@ -114,35 +110,17 @@ func exec(ss []string) {
 }
 
 func handleSetRest(ss []string) {
-	if len(ss) != 4 {
+	if len(ss) != 2 {
 		fmt.Println("invalid set/reset input")
 		fmt.Println(setResetUsage)
 		return
 	}
-	if ss[1] != "D" && ss[1] != "A" && ss[1] != "H" && ss[1] != "W" {
-		fmt.Println(supportMemoryType, "your input: "+ss[1])
-		return
-	}
-	area, ok := getMemoryArea(ss[1], "b")
-	if !ok {
-		fmt.Println("invalid memory type and data type: ", ss[1], "b")
-		return
-	}
-	addrI, err := strconv.Atoi(ss[2])
-	if err != nil || addrI < 0 || addrI > 65535 {
-		fmt.Println("invalid address: " + ss[2])
-		return
-	}
-	offsetI, err := strconv.Atoi(ss[3])
-	if err != nil || offsetI < 0 || offsetI > 255 {
-		fmt.Println("invalid offset: " + ss[3])
+	addr, err := fins.ParseAddress(ss[1])
+	if err != nil {
+		fmt.Println(err)
 		return
 	}
-	if ss[0] == "set" {
-		err = client.SetBit(area, uint16(addrI), byte(offsetI))
-	} else {
-		err = client.ResetBit(area, uint16(addrI), byte(offsetI))
-	}
+	err = client.WriteBitsAt(context.Background(), addr, []bool{ss[0] == "set"})
 	if err != nil {
 		fmt.Println(ss[0] + " error: " + err.Error())
 	} else {
@@ -189,43 +167,17 @@ func string2words(s string) ([]uint16, error) {
 	return bs, nil
 }
 
-func getMemoryArea(mt, dt string) (byte, bool) {
-	switch mt {
-	case "D":
-		if dt == "b" {
-			return fins.MemoryAreaDMBit, true
-		}
-		return fins.MemoryAreaDMWord, true
-	case "A":
-		if dt == "b" {
-			return fins.MemoryAreaARBit, true
-		}
-		return fins.MemoryAreaARWord, true
-	case "H":
-		if dt == "b" {
-			return fins.MemoryAreaHRBit, true
-		}
-		return fins.MemoryAreaHRWord, true
-	case "W":
-		if dt == "b" {
-			return fins.MemoryAreaWRBit, true
-		}
-		return fins.MemoryAreaWRWord, true
-	}
-	return 0, false
-}
-
 const (
-	supportMemoryType = "support memory type: D for DM Area, A for Auxiliary Area, H for Holding Bit Area, W for Work Area"
-	supportDataType   = "support data type: b for Bit, B for Byte, s for String, w for Word"
-	readUsage         = "read usage:  r <memory type> <data type> <address> <count>  example: r A w 100 1"
-	writeUsage        = "write usage: w <memory type> <data type> <address> <values> example: w A w 100 1,2,3"
-	setResetUsage     = "set/reset usage: set/reset <memory type> <address> <offset>"
-	singleCmdUsage    = "single cmd usage: `close` for close client conn; `rc` for read clock"
+	supportAddress  = "support address: Omron notation like D100, W0.05, CIO10, H1, A200, E3_200, T10, C10, IR0, DR0, TK0"
+	supportDataType = "support data type: b for Bit, B for Byte, s for String, w for Word"
+	readUsage       = "read usage:  r <data type> <address> <count>  example: r w A100 1"
+	writeUsage      = "write usage: w <data type> <address> <values> example: w w A100 1,2,3"
+	setResetUsage   = "set/reset usage: set/reset <address>  example: set W0.05"
+	singleCmdUsage  = "single cmd usage: `close` for close client conn; `rc` for read clock"
 )
 
 func help() {
-	fmt.Println(supportMemoryType)
+	fmt.Println(supportAddress)
 	fmt.Println(supportDataType)
 	fmt.Println(readUsage)
 	fmt.Println(writeUsage)
@@ -233,7 +185,7 @@ func help() {
 	fmt.Println(singleCmdUsage)
 }
 
-func processInputCmdAndShowHelp(ss []string) (rw, mt, dt string, addr, count uint16, values any, exit bool) {
+func processInputCmdAndShowHelp(ss []string) (rw, dt string, addr fins.Address, count uint16, values any, exit bool) {
 	if ss[0] == "h" || ss[0] == "help" {
 		help()
 		exit = true
@@ -246,48 +198,40 @@ func processInputCmdAndShowHelp(ss []string) (rw, mt, dt string, addr, count uin
 		exit = true
 		return
 	}
-	if ss[0] == "r" && len(ss) != 5 {
+	if ss[0] == "r" && len(ss) != 4 {
 		fmt.Println("invalid read cmd")
 		fmt.Println(readUsage)
 		exit = true
 		return
 	}
-	if ss[0] == "w" && len(ss) < 5 {
+	if ss[0] == "w" && len(ss) < 4 {
 		fmt.Println("invalid write input")
 		fmt.Println(writeUsage)
 		exit = true
 		return
 	}
-	if ss[0] == "w" && len(ss) > 5 {
-		ss[4] = strings.Join(ss[4:], ",")
-		ss = ss[:5]
+	if ss[0] == "w" && len(ss) > 4 {
+		ss[3] = strings.Join(ss[3:], ",")
+		ss = ss[:4]
 	}
 
-	if ss[1] != "D" && ss[1] != "A" && ss[1] != "H" && ss[1] != "W" {
-		fmt.Println(supportMemoryType, "your input: "+ss[1])
+	if ss[1] != "b" && ss[1] != "B" && ss[1] != "s" && ss[1] != "w" {
+		fmt.Println(supportDataType, "your input: "+ss[1])
 		exit = true
 		return
 	}
 
-	if ss[2] != "b" && ss[2] != "B" && ss[2] != "s" && ss[2] != "w" {
-		fmt.Println(supportDataType, "your input: "+ss[2])
-		exit = true
-		return
-	}
-
-	addrI, err := strconv.Atoi(ss[3])
-	if err != nil || addrI < 0 || addrI > 65535 {
-		fmt.Println("invalid address: " + ss[3])
+	addr, err := fins.ParseAddress(ss[2])
+	if err != nil {
+		fmt.Println(err)
 		exit = true
 		return
 	}
 
-	addr = uint16(addrI)
 	rw = ss[0]
-	mt = ss[1]
-	dt = ss[2]
+	dt = ss[1]
 	if rw == "w" {
-		valuesStr := ss[4]
+		valuesStr := ss[3]
 		switch dt {
 		case "b":
 			values, err = string2bools(valuesStr)
@@ -304,9 +248,9 @@ func processInputCmdAndShowHelp(ss []string) (rw, mt, dt string, addr, count uin
 			return
 		}
 	} else {
-		countI, er := strconv.Atoi(ss[4])
+		countI, er := strconv.Atoi(ss[3])
 		if er != nil || countI < 0 || countI > 65535 {
-			fmt.Println("invalid count: " + ss[4])
+			fmt.Println("invalid count: " + ss[3])
 			exit = true
 			return
 		}
@@ -86,40 +86,40 @@ const (
 	CommandCodeFINSWriteAccessLogWrite uint16 = 0x2141
 
 	// CommandCodeFileNameRead Command code: file name read
-	CommandCodeFileNameRead uint16 = 0x2101
+	CommandCodeFileNameRead uint16 = 0x2201
 
 	// CommandCodeSingleFileRead Command code: file read
-	CommandCodeSingleFileRead uint16 = 0x2102
+	CommandCodeSingleFileRead uint16 = 0x2202
 
 	// CommandCodeSingleFileWrite Command code: file write
-	CommandCodeSingleFileWrite uint16 = 0x2103
+	CommandCodeSingleFileWrite uint16 = 0x2203
 
 	// CommandCodeFileMemoryFormat Command code: file memory format
-	CommandCodeFileMemoryFormat uint16 = 0x2104
+	CommandCodeFileMemoryFormat uint16 = 0x2204
 
 	// CommandCodeFileDelete Command code: file delete
-	CommandCodeFileDelete uint16 = 0x2105
+	CommandCodeFileDelete uint16 = 0x2205
 
 	// CommandCodeFileCopy Command code: file copy
-	CommandCodeFileCopy uint16 = 0x2107
+	CommandCodeFileCopy uint16 = 0x2207
 
 	// CommandCodeFileNameChange Command code: file name change
-	CommandCodeFileNameChange uint16 = 0x2108
+	CommandCodeFileNameChange uint16 = 0x2208
 
 	// CommandCodeMemoryAreaFileTransfer Command code: memory area file transfer
-	CommandCodeMemoryAreaFileTransfer uint16 = 0x210a
+	CommandCodeMemoryAreaFileTransfer uint16 = 0x220a
 
 	// CommandCodeParameterAreaFileTransfer Command code: parameter area file transfer
-	CommandCodeParameterAreaFileTransfer uint16 = 0x210b
+	CommandCodeParameterAreaFileTransfer uint16 = 0x220b
 
 	// CommandCodeProgramAreaFileTransfer Command code: program area file transfer
-	CommandCodeProgramAreaFileTransfer uint16 = 0x210b
+	CommandCodeProgramAreaFileTransfer uint16 = 0x220c
 
 	// CommandCodeDirectoryCreateDelete Command code: directory create/delete
-	CommandCodeDirectoryCreateDelete uint16 = 0x2115
+	CommandCodeDirectoryCreateDelete uint16 = 0x2215
 
 	// CommandCodeMemoryCassetteTransfer Command code: memory cassette transfer (CP1H and CP1L CPU units only)
-	CommandCodeMemoryCassetteTransfer uint16 = 0x2120
+	CommandCodeMemoryCassetteTransfer uint16 = 0x2220
 
 	// CommandCodeForcedSetReset Command code: forced set/reset
 	CommandCodeForcedSetReset uint16 = 0x2301
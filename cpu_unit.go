@@ -0,0 +1,242 @@
+package fins
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"strings"
+)
+
+const (
+	// CPUStatusStop CPU unit status: program is stopped
+	CPUStatusStop byte = 0x00
+
+	// CPUStatusRun CPU unit status: program is running
+	CPUStatusRun byte = 0x01
+
+	// CPUStatusStandby CPU unit status: CPU on standby
+	CPUStatusStandby byte = 0x80
+)
+
+const cpuUnitStatusSize = 26
+
+const (
+	// cpuUnitDataSize model, version, system and area data of CPU unit data read response
+	cpuUnitDataSize = 92
+
+	// cpuUnitConfigSize CPU bus unit configuration, remote I/O data and CPU unit information of CPU unit data read response
+	cpuUnitConfigSize = 66
+)
+
+// CPUUnitData CPU unit data read by ReadCPUUnitData
+type CPUUnitData struct {
+	Model            string // CPU unit model, like "CJ2M-CPU33"
+	Version          string // CPU unit internal system version
+	DIPSwitch        byte   // DIP switch settings, pin 1 is bit 0
+	LargestEMBank    byte   // largest EM bank number
+	ProgramAreaSize  uint16 // size of user program area in K words
+	IOMSize          byte   // size of I/O memory area in K bytes
+	DMWords          uint16 // number of DM words
+	TimerCounterSize byte   // number of timers and counters in units of 1024
+	EMBanks          byte   // number of EM banks used as non-file memory
+	MemoryCardType   byte   // 0 none, 4 flash ROM
+	MemoryCardSize   uint16 // size of memory card in K bytes
+	CPUBusUnits      [16]uint16
+	RemoteIOData     byte // number of SYSMAC BUS masters mounted
+	CPUUnitInfo      byte // CPU unit information
+}
+
+// CPUStatus CPU unit status read by ReadCPUStatus
+type CPUStatus struct {
+	Status             byte   // CPUStatusStop, CPUStatusRun or CPUStatusStandby
+	Mode               byte   // OperatingModeProgram, OperatingModeMonitor or OperatingModeRun
+	FatalErrorFlags    uint16 // fatal error data, one bit per error
+	NonFatalErrorFlags uint16 // non-fatal error data, one bit per error
+	MessageFlags       uint16 // bit n is set if message n exists
+	ErrorCode          uint16 // code of the most serious current error, FAL/FALS number for FAL/FALS errors
+	ErrorMessage       string // message of the FAL/FALS instruction
+}
+
+// Running program is running
+func (s *CPUStatus) Running() bool {
+	return s.Status == CPUStatusRun
+}
+
+// FatalError any fatal error exists, the CPU unit stops running
+func (s *CPUStatus) FatalError() bool {
+	return s.FatalErrorFlags != 0
+}
+
+// NonFatalError any non-fatal error exists, the CPU unit keeps running
+func (s *CPUStatus) NonFatalError() bool {
+	return s.NonFatalErrorFlags != 0
+}
+
+// ReadCPUStatus Reads the CPU unit status
+func (c *Client) ReadCPUStatus() (*CPUStatus, error) {
+	return c.ReadCPUStatusContext(context.Background())
+}
+
+// ReadCPUStatusContext same as ReadCPUStatus, stops waiting for the response when ctx is done
+func (c *Client) ReadCPUStatusContext(ctx context.Context) (*CPUStatus, error) {
+	return wrapRead(c, func() (*CPUStatus, error) {
+		r, e := c.sendCommandAndCheckResponse(ctx, cpuUnitStatusReadCommand())
+		if e != nil {
+			return nil, e
+		}
+		return decodeCPUStatus(r.data)
+	})
+}
+
+// ReadCPUUnitData Reads model, version and memory sizes of the CPU unit
+// CPUBusUnits, RemoteIOData and CPUUnitInfo are left zero if the PLC doesn't send the unit configuration
+func (c *Client) ReadCPUUnitData() (*CPUUnitData, error) {
+	return c.ReadCPUUnitDataContext(context.Background())
+}
+
+// ReadCPUUnitDataContext same as ReadCPUUnitData, stops waiting for the response when ctx is done
+func (c *Client) ReadCPUUnitDataContext(ctx context.Context) (*CPUUnitData, error) {
+	return wrapRead(c, func() (*CPUUnitData, error) {
+		r, e := c.sendCommandAndCheckResponse(ctx, cpuUnitDataReadCommand())
+		if e != nil {
+			return nil, e
+		}
+		return decodeCPUUnitData(r.data)
+	})
+}
+
+// maxWordsPerFrameOfModel max words of one memory area frame by CPU unit model prefix,
+// CPU units listed here accept 999 words per read and 996 words per write over Ethernet
+var maxWordsPerFrameOfModel = []struct {
+	prefix string
+	words  uint16
+}{
+	{"CS1", DefaultMaxWordsPerFrame},
+	{"CJ1", DefaultMaxWordsPerFrame},
+	{"CJ2", DefaultMaxWordsPerFrame},
+	{"NSJ", DefaultMaxWordsPerFrame},
+	{"NJ", DefaultMaxWordsPerFrame},
+	{"NX", DefaultMaxWordsPerFrame},
+}
+
+// MaxWordsPerFrameOf returns max words read or written by one frame of a CPU unit model like "CJ2M-CPU33".
+// ok is false for models not known here, like CV or CP1 series whose limit depends on the unit and network
+// the frames go through, set their limit with SetMaxWordsPerFrame
+func MaxWordsPerFrameOf(model string) (words uint16, ok bool) {
+	model = strings.ToUpper(strings.TrimSpace(model))
+	for _, m := range maxWordsPerFrameOfModel {
+		if strings.HasPrefix(model, m.prefix) {
+			return m.words, true
+		}
+	}
+	return 0, false
+}
+
+// SetMaxWordsPerFrameOfCPUUnit Reads the CPU unit model and sets max words per frame to MaxWordsPerFrameOf it,
+// the limit is kept and UnknownCPUModelError is returned if the model is not known
+func (c *Client) SetMaxWordsPerFrameOfCPUUnit() error {
+	return c.SetMaxWordsPerFrameOfCPUUnitContext(context.Background())
+}
+
+// SetMaxWordsPerFrameOfCPUUnitContext same as SetMaxWordsPerFrameOfCPUUnit, stops waiting for the response when ctx is done
+func (c *Client) SetMaxWordsPerFrameOfCPUUnitContext(ctx context.Context) error {
+	d, err := c.ReadCPUUnitDataContext(ctx)
+	if err != nil {
+		return err
+	}
+	words, ok := MaxWordsPerFrameOf(d.Model)
+	if !ok {
+		return UnknownCPUModelError{d.Model}
+	}
+	c.SetMaxWordsPerFrame(words)
+	return nil
+}
+
+func decodeCPUUnitData(data []byte) (*CPUUnitData, error) {
+	if len(data) < cpuUnitDataSize {
+		return nil, ResponseLengthError{want: cpuUnitDataSize, got: len(data)}
+	}
+	d := &CPUUnitData{
+		Model:            decodeASCII(data[0:20]),
+		Version:          decodeASCII(data[20:40]),
+		DIPSwitch:        data[40],
+		LargestEMBank:    data[41],
+		ProgramAreaSize:  binary.BigEndian.Uint16(data[80:82]),
+		IOMSize:          data[82],
+		DMWords:          binary.BigEndian.Uint16(data[83:85]),
+		TimerCounterSize: data[85],
+		EMBanks:          data[86],
+		MemoryCardType:   data[89],
+		MemoryCardSize:   binary.BigEndian.Uint16(data[90:92]),
+	}
+	data = data[cpuUnitDataSize:]
+	if len(data) == 0 {
+		return d, nil
+	}
+	if len(data) < cpuUnitConfigSize {
+		return nil, ResponseLengthError{want: cpuUnitDataSize + cpuUnitConfigSize, got: cpuUnitDataSize + len(data)}
+	}
+	for i := range d.CPUBusUnits {
+		d.CPUBusUnits[i] = binary.BigEndian.Uint16(data[i*2 : i*2+2])
+	}
+	d.RemoteIOData = data[64]
+	d.CPUUnitInfo = data[65]
+	return d, nil
+}
+
+func encodeCPUUnitData(d *CPUUnitData) []byte {
+	data := make([]byte, cpuUnitDataSize+cpuUnitConfigSize)
+	copy(data[0:20], d.Model)
+	copy(data[20:40], d.Version)
+	data[40] = d.DIPSwitch
+	data[41] = d.LargestEMBank
+	binary.BigEndian.PutUint16(data[80:82], d.ProgramAreaSize)
+	data[82] = d.IOMSize
+	binary.BigEndian.PutUint16(data[83:85], d.DMWords)
+	data[85] = d.TimerCounterSize
+	data[86] = d.EMBanks
+	data[89] = d.MemoryCardType
+	binary.BigEndian.PutUint16(data[90:92], d.MemoryCardSize)
+	config := data[cpuUnitDataSize:]
+	for i, unit := range d.CPUBusUnits {
+		binary.BigEndian.PutUint16(config[i*2:i*2+2], unit)
+	}
+	config[64] = d.RemoteIOData
+	config[65] = d.CPUUnitInfo
+	return data
+}
+
+func decodeCPUStatus(data []byte) (*CPUStatus, error) {
+	if len(data) < cpuUnitStatusSize {
+		return nil, ResponseLengthError{want: cpuUnitStatusSize, got: len(data)}
+	}
+	return &CPUStatus{
+		Status:             data[0],
+		Mode:               data[1],
+		FatalErrorFlags:    binary.BigEndian.Uint16(data[2:4]),
+		NonFatalErrorFlags: binary.BigEndian.Uint16(data[4:6]),
+		MessageFlags:       binary.BigEndian.Uint16(data[6:8]),
+		ErrorCode:          binary.BigEndian.Uint16(data[8:10]),
+		ErrorMessage:       decodeASCII(data[10:26]),
+	}, nil
+}
+
+func encodeCPUStatus(s *CPUStatus) []byte {
+	data := make([]byte, cpuUnitStatusSize)
+	data[0] = s.Status
+	data[1] = s.Mode
+	binary.BigEndian.PutUint16(data[2:4], s.FatalErrorFlags)
+	binary.BigEndian.PutUint16(data[4:6], s.NonFatalErrorFlags)
+	binary.BigEndian.PutUint16(data[6:8], s.MessageFlags)
+	binary.BigEndian.PutUint16(data[8:10], s.ErrorCode)
+	copy(data[10:26], s.ErrorMessage)
+	return data
+}
+
+// decodeASCII trims NUL and space padding of fixed size ASCII fields
+func decodeASCII(data []byte) string {
+	if n := bytes.IndexByte(data, 0); n != -1 {
+		data = data[:n]
+	}
+	return string(bytes.TrimRight(data, " "))
+}
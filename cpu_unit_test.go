@@ -0,0 +1,125 @@
+package fins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ReadCPUStatus(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	status, err := c.ReadCPUStatus()
+	assert.Nil(t, err)
+	assert.Equal(t, &CPUStatus{Status: CPUStatusStop, Mode: OperatingModeProgram}, status)
+	assert.False(t, status.Running())
+	assert.False(t, status.FatalError())
+
+	assert.Nil(t, c.Run(OperatingModeMonitor, ProgramNumberAll))
+	s.nonFatalErrorFlags = 0x8000
+	s.errorCode = 0x4101
+	s.errorMessage = "LOW PRESSURE"
+	status, err = c.ReadCPUStatus()
+	assert.Nil(t, err)
+	assert.True(t, status.Running())
+	assert.Equal(t, OperatingModeMonitor, status.Mode)
+	assert.False(t, status.FatalError())
+	assert.True(t, status.NonFatalError())
+	assert.Equal(t, uint16(0x4101), status.ErrorCode)
+	assert.Equal(t, "LOW PRESSURE", status.ErrorMessage)
+}
+
+func Test_decodeCPUStatus(t *testing.T) {
+	_, err := decodeCPUStatus(make([]byte, 10))
+	assert.Equal(t, ResponseLengthError{want: cpuUnitStatusSize, got: 10}, err)
+
+	data := []byte{0x01, 0x04, 0x40, 0x00, 0x00, 0x00, 0x00, 0x01, 0xC1, 0x01,
+		'E', 'M', 'E', 'R', 'G', 'E', 'N', 'C', 'Y', ' ', ' ', ' ', ' ', ' ', ' ', ' '}
+	status, err := decodeCPUStatus(data)
+	assert.Nil(t, err)
+	assert.True(t, status.FatalError())
+	assert.Equal(t, uint16(0x0001), status.MessageFlags)
+	assert.Equal(t, "EMERGENCY", status.ErrorMessage)
+}
+
+func TestClient_ReadCPUUnitData(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	d, err := c.ReadCPUUnitData()
+	assert.Nil(t, err)
+	assert.Equal(t, "CJ2M-CPU33", d.Model)
+	assert.Equal(t, uint16(DmAreaSize), d.DMWords)
+
+	want := CPUUnitData{
+		Model:           "CS1H-CPU67H",
+		Version:         "04.00",
+		DIPSwitch:       0x20,
+		LargestEMBank:   12,
+		ProgramAreaSize: 250,
+		IOMSize:         23,
+		DMWords:         32768,
+		EMBanks:         13,
+		MemoryCardType:  4,
+		MemoryCardSize:  30000,
+		RemoteIOData:    1,
+	}
+	want.CPUBusUnits[0] = 0x0101
+	s.SetCPUUnitData(want)
+	d, err = c.ReadCPUUnitData()
+	assert.Nil(t, err)
+	assert.Equal(t, &want, d)
+}
+
+func Test_decodeCPUUnitData(t *testing.T) {
+	data := encodeCPUUnitData(&CPUUnitData{Model: "CP1L-EM40DR-D", EMBanks: 1, CPUBusUnits: [16]uint16{1}})
+
+	d, err := decodeCPUUnitData(data[:cpuUnitDataSize])
+	assert.Nil(t, err)
+	assert.Equal(t, &CPUUnitData{Model: "CP1L-EM40DR-D", EMBanks: 1}, d)
+
+	_, err = decodeCPUUnitData(data[:cpuUnitDataSize+10])
+	assert.Equal(t, ResponseLengthError{want: cpuUnitDataSize + cpuUnitConfigSize, got: cpuUnitDataSize + 10}, err)
+
+	_, err = decodeCPUUnitData(data[:20])
+	assert.Equal(t, ResponseLengthError{want: cpuUnitDataSize, got: 20}, err)
+}
+
+func TestSimulator_stopKeepsCPUUnitData(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	s.SetCPUUnitData(CPUUnitData{Model: "CJ2H-CPU68"})
+	assert.Nil(t, c.Run(OperatingModeRun, ProgramNumberAll))
+	assert.Nil(t, c.Stop())
+	d, err := c.ReadCPUUnitData()
+	assert.Nil(t, err)
+	assert.Equal(t, "CJ2H-CPU68", d.Model)
+}
+
+func TestMaxWordsPerFrameOf(t *testing.T) {
+	for _, model := range []string{"CJ2M-CPU33", "CS1G-CPU42H", "cj1m-cpu11", "NJ501-1300", "NX102-9000"} {
+		words, ok := MaxWordsPerFrameOf(model)
+		assert.True(t, ok, model)
+		assert.Equal(t, uint16(DefaultMaxWordsPerFrame), words, model)
+	}
+	for _, model := range []string{"CP1L-EM40DR-D", "CV1000", ""} {
+		_, ok := MaxWordsPerFrameOf(model)
+		assert.False(t, ok, model)
+	}
+}
+
+func TestClient_SetMaxWordsPerFrameOfCPUUnit(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	c.SetMaxWordsPerFrame(100)
+	assert.Nil(t, c.SetMaxWordsPerFrameOfCPUUnit())
+	assert.Equal(t, DefaultMaxWordsPerFrame, c.MaxWordsPerFrame())
+
+	c.SetMaxWordsPerFrame(100)
+	s.SetCPUUnitData(CPUUnitData{Model: "CP1L-EM40DR-D"})
+	assert.Equal(t, UnknownCPUModelError{"CP1L-EM40DR-D"}, c.SetMaxWordsPerFrameOfCPUUnit())
+	assert.Equal(t, 100, c.MaxWordsPerFrame())
+}
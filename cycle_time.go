@@ -0,0 +1,72 @@
+package fins
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+)
+
+const (
+	// cycleTimeInitialize cycle time read parameter: initializes average, max and min cycle time
+	cycleTimeInitialize byte = 0x00
+
+	// cycleTimeRead cycle time read parameter: reads average, max and min cycle time
+	cycleTimeRead byte = 0x01
+
+	// cycleTimeUnit cycle times are sent in units of 0.1 ms
+	cycleTimeUnit = 100 * time.Microsecond
+)
+
+// CycleTime cycle time statistics read by ReadCycleTime
+type CycleTime struct {
+	Average time.Duration
+	Max     time.Duration
+	Min     time.Duration
+}
+
+// ReadCycleTime Reads average, max and min cycle time since the last ResetCycleTime
+func (c *Client) ReadCycleTime() (*CycleTime, error) {
+	return c.ReadCycleTimeContext(context.Background())
+}
+
+// ReadCycleTimeContext same as ReadCycleTime, stops waiting for the response when ctx is done
+func (c *Client) ReadCycleTimeContext(ctx context.Context) (*CycleTime, error) {
+	return wrapRead(c, func() (*CycleTime, error) {
+		r, e := c.sendCommandAndCheckResponse(ctx, cycleTimeReadCommand(cycleTimeRead))
+		if e != nil {
+			return nil, e
+		}
+		return decodeCycleTime(r.data)
+	})
+}
+
+// ResetCycleTime Initializes the max and min cycle time
+func (c *Client) ResetCycleTime() error {
+	return c.ResetCycleTimeContext(context.Background())
+}
+
+// ResetCycleTimeContext same as ResetCycleTime, stops waiting for the response when ctx is done
+func (c *Client) ResetCycleTimeContext(ctx context.Context) error {
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, cycleTimeReadCommand(cycleTimeInitialize)))
+	})
+}
+
+func decodeCycleTime(data []byte) (*CycleTime, error) {
+	if len(data) != 12 {
+		return nil, ResponseLengthError{want: 12, got: len(data)}
+	}
+	return &CycleTime{
+		Average: time.Duration(binary.BigEndian.Uint32(data[0:4])) * cycleTimeUnit,
+		Max:     time.Duration(binary.BigEndian.Uint32(data[4:8])) * cycleTimeUnit,
+		Min:     time.Duration(binary.BigEndian.Uint32(data[8:12])) * cycleTimeUnit,
+	}, nil
+}
+
+func encodeCycleTime(t *CycleTime) []byte {
+	data := make([]byte, 12)
+	binary.BigEndian.PutUint32(data[0:4], uint32(t.Average/cycleTimeUnit))
+	binary.BigEndian.PutUint32(data[4:8], uint32(t.Max/cycleTimeUnit))
+	binary.BigEndian.PutUint32(data[8:12], uint32(t.Min/cycleTimeUnit))
+	return data
+}
@@ -0,0 +1,38 @@
+package fins
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ReadCycleTime(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	ct, err := c.ReadCycleTime()
+	assert.Nil(t, err)
+	assert.Equal(t, &CycleTime{defaultSimulatorCycleTime, defaultSimulatorCycleTime, defaultSimulatorCycleTime}, ct)
+
+	s.SetCycleTime(12300 * time.Microsecond)
+	s.SetCycleTime(800 * time.Microsecond)
+	s.SetCycleTime(2 * time.Millisecond)
+	ct, err = c.ReadCycleTime()
+	assert.Nil(t, err)
+	assert.Equal(t, &CycleTime{2 * time.Millisecond, 12300 * time.Microsecond, 800 * time.Microsecond}, ct)
+
+	assert.Nil(t, c.ResetCycleTime())
+	ct, err = c.ReadCycleTime()
+	assert.Nil(t, err)
+	assert.Equal(t, &CycleTime{2 * time.Millisecond, 2 * time.Millisecond, 2 * time.Millisecond}, ct)
+}
+
+func Test_decodeCycleTime(t *testing.T) {
+	ct, err := decodeCycleTime([]byte{0, 0, 0, 0x64, 0, 0, 0x01, 0x2C, 0, 0, 0, 0x0A})
+	assert.Nil(t, err)
+	assert.Equal(t, &CycleTime{10 * time.Millisecond, 30 * time.Millisecond, time.Millisecond}, ct)
+
+	_, err = decodeCycleTime(make([]byte, 8))
+	assert.Equal(t, ResponseLengthError{want: 12, got: 8}, err)
+}
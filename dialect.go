@@ -0,0 +1,113 @@
+package fins
+
+// Dialect memory area codes and address layout used in FINS commands
+// memory areas are always given with the CS/CJ mode constants (MemoryAreaDMWord, MemoryAreaCIOBit...),
+// client translates them to the codes and addresses of its dialect
+type Dialect byte
+
+const (
+	// DialectCSCJ CS/CJ mode, used by CS, CJ, CP and NJ series CPU units
+	DialectCSCJ Dialect = iota
+
+	// DialectCV CV mode, used by CV and C200HX/HG/HE series CPU units
+	// work, holding, index register and data register areas, bits of DM and EM area and EM banks above 7 are not supported
+	DialectCV
+)
+
+// memory area codes of CV mode
+const (
+	cvMemoryAreaCIOBit                     byte = 0x00 // CIO, CPU bus link and auxiliary area; bit
+	cvMemoryAreaTimerCounterCompletionFlag byte = 0x01
+	cvMemoryAreaCIOWord                    byte = 0x80 // CIO, CPU bus link and auxiliary area; word
+	cvMemoryAreaTimerCounterPV             byte = 0x81
+	cvMemoryAreaDMWord                     byte = 0x82
+	cvMemoryAreaEM0Word                    byte = 0x90 // banks 0-7: 0x90-0x97
+	cvMemoryAreaEMCurrentBankWord          byte = 0x98
+)
+
+// cvMemoryAreaRange count addresses from start of a CS/CJ mode memory area
+// are addresses from cvStart of a CV mode memory area
+type cvMemoryAreaRange struct {
+	memoryArea, cvMemoryArea byte
+	start, cvStart, count    uint16
+}
+
+var cvMemoryAreaRanges = []cvMemoryAreaRange{
+	{MemoryAreaCIOBit, cvMemoryAreaCIOBit, 0, 0x0000, 2556},
+	{MemoryAreaARBit, cvMemoryAreaCIOBit, 0, 0x0b00, 512},
+	{MemoryAreaCIOWord, cvMemoryAreaCIOWord, 0, 0x0000, 2556},
+	{MemoryAreaARWord, cvMemoryAreaCIOWord, 0, 0x0b00, 512},
+	// timers T0000-T2047 and counters C0000-C2047
+	{MemoryAreaTimerCounterCompletionFlag, cvMemoryAreaTimerCounterCompletionFlag, 0x0000, 0x0000, 0x0800},
+	{MemoryAreaTimerCounterCompletionFlag, cvMemoryAreaTimerCounterCompletionFlag, 0x8000, 0x0800, 0x0800},
+	{MemoryAreaTimerCounterPV, cvMemoryAreaTimerCounterPV, 0x0000, 0x0000, 0x0800},
+	{MemoryAreaTimerCounterPV, cvMemoryAreaTimerCounterPV, 0x8000, 0x0800, 0x0800},
+	{MemoryAreaDMWord, cvMemoryAreaDMWord, 0, 0, 32768},
+	{MemoryAreaEMCurrentBankWord, cvMemoryAreaEMCurrentBankWord, 0, 0, 32768},
+}
+
+func init() {
+	for bank := byte(0); bank < 8; bank++ {
+		cvMemoryAreaRanges = append(cvMemoryAreaRanges,
+			cvMemoryAreaRange{MemoryAreaEM0Word + bank, cvMemoryAreaEM0Word + bank, 0, 0, 32768})
+	}
+}
+
+// encodeMemoryArea translates memory area code of CS/CJ mode to the dialect
+func (d Dialect) encodeMemoryArea(memoryArea byte) (byte, error) {
+	if d != DialectCV {
+		return memoryArea, nil
+	}
+	for _, r := range cvMemoryAreaRanges {
+		if r.memoryArea == memoryArea {
+			return r.cvMemoryArea, nil
+		}
+	}
+	return 0, IncompatibleMemoryAreaError{memoryArea}
+}
+
+// encodeMemoryAddress translates memory address of CS/CJ mode to the dialect
+func (d Dialect) encodeMemoryAddress(addr memoryAddress) (memoryAddress, error) {
+	if d != DialectCV {
+		return addr, nil
+	}
+	supported := false
+	for _, r := range cvMemoryAreaRanges {
+		if r.memoryArea != addr.memoryArea {
+			continue
+		}
+		supported = true
+		if addr.address >= r.start && addr.address-r.start < r.count {
+			return memoryAddress{r.cvMemoryArea, addr.address - r.start + r.cvStart, addr.bitOffset}, nil
+		}
+	}
+	if supported {
+		return addr, MemoryAddressRangeError{d, addr.memoryArea, addr.address}
+	}
+	return addr, IncompatibleMemoryAreaError{addr.memoryArea}
+}
+
+// decodeMemoryAddress translates memory address of the dialect to CS/CJ mode
+func (d Dialect) decodeMemoryAddress(addr memoryAddress) (memoryAddress, bool) {
+	if d != DialectCV {
+		return addr, true
+	}
+	for _, r := range cvMemoryAreaRanges {
+		if r.cvMemoryArea == addr.memoryArea && addr.address >= r.cvStart && addr.address-r.cvStart < r.count {
+			return memoryAddress{r.memoryArea, addr.address - r.cvStart + r.start, addr.bitOffset}, true
+		}
+	}
+	return addr, false
+}
+
+// String name of the dialect
+func (d Dialect) String() string {
+	switch d {
+	case DialectCSCJ:
+		return "CS/CJ mode"
+	case DialectCV:
+		return "CV mode"
+	default:
+		return "unknown dialect"
+	}
+}
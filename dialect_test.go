@@ -0,0 +1,81 @@
+package fins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialect_encodeMemoryAddress(t *testing.T) {
+	for _, tt := range []struct {
+		addr, want memoryAddress
+	}{
+		{memoryAddress{MemoryAreaCIOBit, 10, 3}, memoryAddress{0x00, 10, 3}},
+		{memoryAddress{MemoryAreaARWord, 5, 0}, memoryAddress{0x80, 0x0b05, 0}},
+		{memoryAddress{MemoryAreaTimerCounterPV, 0x0010, 0}, memoryAddress{0x81, 0x0010, 0}},
+		{memoryAddress{MemoryAreaTimerCounterCompletionFlag, 0x8010, 0}, memoryAddress{0x01, 0x0810, 0}},
+		{memoryAddress{MemoryAreaDMWord, 100, 0}, memoryAddress{0x82, 100, 0}},
+		{memoryAddress{MemoryAreaEM7Word, 100, 0}, memoryAddress{0x97, 100, 0}},
+		{memoryAddress{MemoryAreaEMCurrentBankWord, 100, 0}, memoryAddress{0x98, 100, 0}},
+	} {
+		got, err := DialectCV.encodeMemoryAddress(tt.addr)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.want, got)
+		decoded, ok := DialectCV.decodeMemoryAddress(got)
+		assert.True(t, ok)
+		assert.Equal(t, tt.addr, decoded)
+	}
+
+	_, err := DialectCV.encodeMemoryAddress(memAddr(MemoryAreaHRWord, 0))
+	assert.Equal(t, IncompatibleMemoryAreaError{MemoryAreaHRWord}, err)
+	_, err = DialectCV.encodeMemoryAddress(memAddr(MemoryAreaTimerCounterPV, 0x0800))
+	assert.Equal(t, MemoryAddressRangeError{DialectCV, MemoryAreaTimerCounterPV, 0x0800}, err)
+
+	addr := memAddr(MemoryAreaHRWord, 0)
+	got, err := DialectCSCJ.encodeMemoryAddress(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, addr, got)
+	_, ok := DialectCV.decodeMemoryAddress(memAddr(MemoryAreaHRWord, 0))
+	assert.False(t, ok)
+}
+
+func TestClient_DialectCV(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+	c.SetDialect(DialectCV)
+	s.SetDialect(DialectCV)
+	assert.Equal(t, DialectCV, c.Dialect())
+
+	assert.Nil(t, c.WriteWords(MemoryAreaARWord, 5, []uint16{0x1234}))
+	words, err := c.ReadWords(MemoryAreaARWord, 5, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0x1234}, words)
+	value, _ := s.read(memAddr(MemoryAreaARWord, 5), 1)
+	assert.Equal(t, []byte{0x12, 0x34}, value)
+
+	assert.Nil(t, c.WriteWords(MemoryAreaTimerCounterPV, 0x8003, []uint16{7}))
+	value, _ = s.read(memAddr(MemoryAreaTimerCounterPV, 0x8003), 1)
+	assert.Equal(t, []byte{0x00, 0x07}, value)
+
+	assert.Nil(t, c.SetBit(MemoryAreaCIOBit, 0, 2))
+	assert.Nil(t, c.ForceSet(MemoryAreaCIOBit, 0, 3))
+	values, err := c.ReadMultiple([]MultipleReadItem{
+		{MemoryArea: MemoryAreaCIOWord, Address: 0},
+		{MemoryArea: MemoryAreaTimerCounterPV, Address: 0x8003},
+		{MemoryArea: MemoryAreaARBit, Address: 5, BitOffset: 2},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(0x000c), values[0].Word)
+	assert.Equal(t, uint16(7), values[1].Word)
+	assert.Equal(t, true, values[2].Bit)
+
+	assert.Equal(t, IncompatibleMemoryAreaError{MemoryAreaWRWord}, c.WriteWords(MemoryAreaWRWord, 0, []uint16{1}))
+
+	// CS/CJ mode client can't access CIO of CV mode PLC
+	c.SetDialect(DialectCSCJ)
+	_, err = c.ReadWords(MemoryAreaCIOWord, 0, 1)
+	assert.Equal(t, EndCodeError{EndCodeAreaClassificationMissing}, err)
+	words, err = c.ReadWords(MemoryAreaDMWord, 0, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0}, words)
+}
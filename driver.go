@@ -59,12 +59,243 @@ func writeCommand(memoryAddr memoryAddress, itemCount uint16, bytes []byte) []by
 	return commandData
 }
 
+func fillCommand(memoryAddr memoryAddress, itemCount uint16, value []byte) []byte {
+	commandData := make([]byte, 2, 10)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeMemoryAreaFill)
+	commandData = append(commandData, encodeMemoryAddress(memoryAddr)...)
+	commandData = append(commandData, []byte{0, 0}...)
+	binary.BigEndian.PutUint16(commandData[6:8], itemCount)
+	commandData = append(commandData, value...)
+	return commandData
+}
+
+func transferCommand(src, dst memoryAddress, itemCount uint16) []byte {
+	commandData := make([]byte, 2, 12)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeMemoryAreaTransfer)
+	commandData = append(commandData, encodeMemoryAddress(src)...)
+	commandData = append(commandData, encodeMemoryAddress(dst)...)
+	commandData = append(commandData, []byte{0, 0}...)
+	binary.BigEndian.PutUint16(commandData[10:12], itemCount)
+	return commandData
+}
+
+func multipleReadCommand(memoryAddrs []memoryAddress) []byte {
+	commandData := make([]byte, 2, 2+4*len(memoryAddrs))
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeMultipleMemoryAreaRead)
+	for _, addr := range memoryAddrs {
+		commandData = append(commandData, encodeMemoryAddress(addr)...)
+	}
+	return commandData
+}
+
+func runCommand(programNumber uint16, mode byte) []byte {
+	commandData := make([]byte, 5, 5)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeRun)
+	binary.BigEndian.PutUint16(commandData[2:4], programNumber)
+	commandData[4] = mode
+	return commandData
+}
+
+func stopCommand(programNumber uint16) []byte {
+	commandData := make([]byte, 4, 4)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeStop)
+	binary.BigEndian.PutUint16(commandData[2:4], programNumber)
+	return commandData
+}
+
+func cpuUnitDataReadCommand() []byte {
+	commandData := make([]byte, 2, 2)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeCPUUnitDataRead)
+	return commandData
+}
+
+func cpuUnitStatusReadCommand() []byte {
+	commandData := make([]byte, 2, 2)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeCPUUnitStatusRead)
+	return commandData
+}
+
+func cycleTimeReadCommand(parameter byte) []byte {
+	commandData := make([]byte, 3, 3)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeCycleTimeRead)
+	commandData[2] = parameter
+	return commandData
+}
+
+func errorClearCommand(errorCode uint16) []byte {
+	commandData := make([]byte, 4, 4)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeErrorClear)
+	binary.BigEndian.PutUint16(commandData[2:4], errorCode)
+	return commandData
+}
+
+func errorLogReadCommand(start, count uint16) []byte {
+	commandData := make([]byte, 6, 6)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeErrorLogRead)
+	binary.BigEndian.PutUint16(commandData[2:4], start)
+	binary.BigEndian.PutUint16(commandData[4:6], count)
+	return commandData
+}
+
+func errorLogClearCommand() []byte {
+	commandData := make([]byte, 2, 2)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeErrorLogClear)
+	return commandData
+}
+
+func accessRightCommand(commandCode uint16, programNumber uint16) []byte {
+	commandData := make([]byte, 4, 4)
+	binary.BigEndian.PutUint16(commandData[0:2], commandCode)
+	binary.BigEndian.PutUint16(commandData[2:4], programNumber)
+	return commandData
+}
+
+func forcedSetResetCommand(bits []ForcedBit) []byte {
+	commandData := make([]byte, 4, 4+len(bits)*6)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeForcedSetReset)
+	binary.BigEndian.PutUint16(commandData[2:4], uint16(len(bits)))
+	for _, bit := range bits {
+		commandData = binary.BigEndian.AppendUint16(commandData, bit.Spec)
+		commandData = append(commandData, encodeMemoryAddress(memAddrWithBitOffset(bit.MemoryArea, bit.Address, bit.BitOffset))...)
+	}
+	return commandData
+}
+
+func forcedSetResetCancelCommand() []byte {
+	commandData := make([]byte, 2, 2)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeForcedSetResetCancel)
+	return commandData
+}
+
+func messageReadClearCommand(parameter uint16) []byte {
+	commandData := make([]byte, 4, 4)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeMessageReadClear)
+	binary.BigEndian.PutUint16(commandData[2:4], parameter)
+	return commandData
+}
+
+func fileNameReadCommand(disk, start, count uint16, dir string) []byte {
+	commandData := make([]byte, 8, 10+len(dir))
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeFileNameRead)
+	binary.BigEndian.PutUint16(commandData[2:4], disk)
+	binary.BigEndian.PutUint16(commandData[4:6], start)
+	binary.BigEndian.PutUint16(commandData[6:8], count)
+	return append(commandData, encodeDirectory(dir)...)
+}
+
+func singleFileReadCommand(disk uint16, name []byte, position uint32, length uint16, dir string) []byte {
+	commandData := make([]byte, 4, 22+len(dir))
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeSingleFileRead)
+	binary.BigEndian.PutUint16(commandData[2:4], disk)
+	commandData = append(commandData, name...)
+	commandData = binary.BigEndian.AppendUint32(commandData, position)
+	commandData = binary.BigEndian.AppendUint16(commandData, length)
+	return append(commandData, encodeDirectory(dir)...)
+}
+
+func singleFileWriteCommand(disk, parameter uint16, name []byte, position uint32, dir string, data []byte) []byte {
+	commandData := make([]byte, 6, 24+len(dir)+len(data))
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeSingleFileWrite)
+	binary.BigEndian.PutUint16(commandData[2:4], disk)
+	binary.BigEndian.PutUint16(commandData[4:6], parameter)
+	commandData = append(commandData, name...)
+	commandData = binary.BigEndian.AppendUint32(commandData, position)
+	commandData = binary.BigEndian.AppendUint16(commandData, uint16(len(data)))
+	commandData = append(commandData, encodeDirectory(dir)...)
+	return append(commandData, data...)
+}
+
+func fileDeleteCommand(disk uint16, names [][]byte, dir string) []byte {
+	commandData := make([]byte, 6, 8+len(names)*fileNameSize+len(dir))
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeFileDelete)
+	binary.BigEndian.PutUint16(commandData[2:4], disk)
+	binary.BigEndian.PutUint16(commandData[4:6], uint16(len(names)))
+	for _, name := range names {
+		commandData = append(commandData, name...)
+	}
+	return append(commandData, encodeDirectory(dir)...)
+}
+
+func fileCopyCommand(srcDisk uint16, srcDir string, srcName []byte, dstDisk uint16, dstDir string, dstName []byte) []byte {
+	commandData := make([]byte, 2, 34+len(srcDir)+len(dstDir))
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeFileCopy)
+	commandData = binary.BigEndian.AppendUint16(commandData, srcDisk)
+	commandData = append(commandData, encodeDirectory(srcDir)...)
+	commandData = append(commandData, srcName...)
+	commandData = binary.BigEndian.AppendUint16(commandData, dstDisk)
+	commandData = append(commandData, encodeDirectory(dstDir)...)
+	return append(commandData, dstName...)
+}
+
+func fileNameChangeCommand(disk uint16, dir string, oldName, newName []byte) []byte {
+	commandData := make([]byte, 4, 30+len(dir))
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeFileNameChange)
+	binary.BigEndian.PutUint16(commandData[2:4], disk)
+	commandData = append(commandData, encodeDirectory(dir)...)
+	commandData = append(commandData, oldName...)
+	return append(commandData, newName...)
+}
+
+func directoryCreateDeleteCommand(disk, parameter uint16, dir string, name []byte) []byte {
+	commandData := make([]byte, 6, 20+len(dir))
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeDirectoryCreateDelete)
+	binary.BigEndian.PutUint16(commandData[2:4], disk)
+	binary.BigEndian.PutUint16(commandData[4:6], parameter)
+	commandData = append(commandData, encodeDirectory(dir)...)
+	return append(commandData, name...)
+}
+
+func parameterAreaCommand(commandCode uint16, area ParameterArea, address, count uint16, data []byte) []byte {
+	commandData := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint16(commandData[0:2], commandCode)
+	binary.BigEndian.PutUint16(commandData[2:4], uint16(area))
+	binary.BigEndian.PutUint16(commandData[4:6], address)
+	binary.BigEndian.PutUint16(commandData[6:8], count)
+	return append(commandData, data...)
+}
+
+func programAreaReadCommand(programNumber uint16, offset uint32, length uint16) []byte {
+	commandData := make([]byte, 10, 10)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeProgramAreaRead)
+	binary.BigEndian.PutUint16(commandData[2:4], programNumber)
+	binary.BigEndian.PutUint32(commandData[4:8], offset)
+	binary.BigEndian.PutUint16(commandData[8:10], length)
+	return commandData
+}
+
+func programAreaWriteCommand(programNumber uint16, offset uint32, data []byte, last bool) []byte {
+	commandData := make([]byte, 10, 10+len(data))
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeProgramAreaWrite)
+	binary.BigEndian.PutUint16(commandData[2:4], programNumber)
+	binary.BigEndian.PutUint32(commandData[4:8], offset)
+	length := uint16(len(data))
+	if last {
+		length |= programLastFlag
+	}
+	binary.BigEndian.PutUint16(commandData[8:10], length)
+	return append(commandData, data...)
+}
+
+func programAreaClearCommand(programNumber uint16) []byte {
+	commandData := make([]byte, 5, 5)
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeProgramAreaClear)
+	binary.BigEndian.PutUint16(commandData[2:4], programNumber)
+	commandData[4] = programClearCode
+	return commandData
+}
+
 func clockReadCommand() []byte {
 	commandData := make([]byte, 2, 2)
 	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeClockRead)
 	return commandData
 }
 
+func clockWriteCommand(clock []byte) []byte {
+	commandData := make([]byte, 2, 2+len(clock))
+	binary.BigEndian.PutUint16(commandData[0:2], CommandCodeClockWrite)
+	return append(commandData, clock...)
+}
+
 func encodeMemoryAddress(memoryAddr memoryAddress) []byte {
 	bytes := make([]byte, 4, 4)
 	bytes[0] = memoryAddr.memoryArea
@@ -168,6 +399,23 @@ func encodeBCD(x uint64) []byte {
 	return bcd
 }
 
+// encodeBCDFixed encodes x into size bytes of BCD, padded with leading zeros
+// encodeBCD can't be used for fixed size fields like clock data: its length depends on x and odd digits end with 0xF (5 -> 0x5F, not 0x05)
+func encodeBCDFixed(x uint64, size int) ([]byte, error) {
+	bcd := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		lo := byte(x % 10)
+		x = x / 10
+		hi := byte(x % 10)
+		x = x / 10
+		bcd[i] = hi<<4 | lo
+	}
+	if x > 0 {
+		return nil, BCDOverflowError{}
+	}
+	return bcd, nil
+}
+
 func timesTenPlusCatchingOverflow(x uint64, digit uint64) (uint64, error) {
 	x5 := x<<2 + x
 	if int64(x5) < 0 || x5<<1 > ^digit {
@@ -176,6 +424,22 @@ func timesTenPlusCatchingOverflow(x uint64, digit uint64) (uint64, error) {
 	return x5<<1 + digit, nil
 }
 
+// decodeBCDFixed decodes size bytes of BCD like words and double words, every digit must be 0-9
+func decodeBCDFixed(bcd []byte) (uint64, error) {
+	var x uint64
+	for _, b := range bcd {
+		hi, lo := uint64(b>>4), uint64(b&0x0f)
+		if hi > 9 {
+			return 0, BCDBadDigitError{"hi", hi}
+		}
+		if lo > 9 {
+			return 0, BCDBadDigitError{"lo", lo}
+		}
+		x = x*100 + hi*10 + lo
+	}
+	return x, nil
+}
+
 func decodeBCD(bcd []byte) (x uint64, err error) {
 	for i, b := range bcd {
 		hi, lo := uint64(b>>4), uint64(b&0x0f)
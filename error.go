@@ -59,6 +59,124 @@ func (e IncompatibleMemoryAreaError) Error() string {
 	return fmt.Sprintf("The memory area is incompatible with the data type to be read: 0x%X", e.area)
 }
 
+type ReadOnlyMemoryAreaError struct {
+	area byte
+}
+
+func (e ReadOnlyMemoryAreaError) Error() string {
+	return fmt.Sprintf("The memory area is read only: 0x%X", e.area)
+}
+
+type MemoryAddressRangeError struct {
+	dialect Dialect
+	area    byte
+	address uint16
+}
+
+func (e MemoryAddressRangeError) Error() string {
+	return fmt.Sprintf("address %d of memory area 0x%X is out of range in %s", e.address, e.area, e.dialect)
+}
+
+type AddressOverflowError struct {
+	area    byte
+	address uint16
+	count   int
+}
+
+func (e AddressOverflowError) Error() string {
+	return fmt.Sprintf("%d items from address %d of memory area 0x%X go past address 65535", e.count, e.address, e.area)
+}
+
+type IncompatibleParameterAreaError struct {
+	area ParameterArea
+}
+
+func (e IncompatibleParameterAreaError) Error() string {
+	return fmt.Sprintf("unknown parameter area: 0x%04X", uint16(e.area))
+}
+
+type ParameterAreaRangeError struct {
+	area           ParameterArea
+	address, count uint16
+}
+
+func (e ParameterAreaRangeError) Error() string {
+	return fmt.Sprintf("parameter area 0x%04X has %d words, can't access %d words from %d",
+		uint16(e.area), e.area.Words(), e.count, e.address)
+}
+
+type InvalidOperatingModeError struct {
+	mode byte
+}
+
+func (e InvalidOperatingModeError) Error() string {
+	return fmt.Sprintf("invalid operating mode for run: 0x%02X", e.mode)
+}
+
+type ClockOutOfRangeError struct {
+	t time.Time
+}
+
+func (e ClockOutOfRangeError) Error() string {
+	return fmt.Sprintf("PLC clock supports year 1950 to 2049, got: %s", e.t.Format(time.RFC3339))
+}
+
+type InvalidFilePathError struct {
+	path string
+}
+
+func (e InvalidFilePathError) Error() string {
+	return fmt.Sprintf("invalid file path, names should be in 8.3 format like \\DIR\\FILE.EXT: %q", e.path)
+}
+
+type InvalidAddressError struct {
+	address string
+}
+
+func (e InvalidAddressError) Error() string {
+	return fmt.Sprintf("invalid address, should be in Omron notation like D100, CIO0.05, E3_200 or T10: %q", e.address)
+}
+
+type InvalidStructError struct {
+	typ string
+}
+
+func (e InvalidStructError) Error() string {
+	return fmt.Sprintf("invalid struct argument, ReadStruct needs a struct pointer and WriteStruct a struct: %s", e.typ)
+}
+
+type StructFieldError struct {
+	field, msg string
+}
+
+func (e StructFieldError) Error() string {
+	return fmt.Sprintf("error struct field %s: %s", e.field, e.msg)
+}
+
+type UnknownCPUModelError struct {
+	model string
+}
+
+func (e UnknownCPUModelError) Error() string {
+	return fmt.Sprintf("max words per frame of CPU unit model %q is unknown, set it with SetMaxWordsPerFrame", e.model)
+}
+
+type NotInProgramModeError struct {
+	mode byte
+}
+
+func (e NotInProgramModeError) Error() string {
+	return fmt.Sprintf("PLC should be in PROGRAM mode, current mode: 0x%02X", e.mode)
+}
+
+type ProgramArchiveError struct {
+	msg string
+}
+
+func (e ProgramArchiveError) Error() string {
+	return "error program archive: " + e.msg
+}
+
 // Driver errors
 
 type BCDBadDigitError struct {
@@ -82,6 +200,12 @@ func (e EmptyPlcUDPAddress) Error() string {
 	return "error empty plc udp address"
 }
 
+type EmptyPlcTCPAddress struct{}
+
+func (e EmptyPlcTCPAddress) Error() string {
+	return "error empty plc tcp address"
+}
+
 type EndCodeError struct {
 	code uint16
 }
@@ -93,3 +217,113 @@ func (e EndCodeError) Error() string {
 func (e EndCodeError) EndCode() uint16 {
 	return e.code
 }
+
+// TransportBrokenError the transport can't receive frames anymore, like a stream misaligned by a bad frame
+// or a reset connection. Client drops the transport and dials a new one on next operation
+type TransportBrokenError struct {
+	err error
+}
+
+func (e TransportBrokenError) Error() string {
+	return "fins transport is broken: " + e.err.Error()
+}
+
+func (e TransportBrokenError) Unwrap() error {
+	return e.err
+}
+
+// FINS/TCP errors
+
+type TCPHeaderError struct {
+	msg string
+}
+
+func (e TCPHeaderError) Error() string {
+	return "error FINS/TCP header: " + e.msg
+}
+
+type TCPErrorCodeError struct {
+	code uint32
+}
+
+func (e TCPErrorCodeError) Error() string {
+	return fmt.Sprintf("error reported by FINS/TCP peer: %s", TCPErrorCodeToMsg(e.code))
+}
+
+func (e TCPErrorCodeError) ErrorCode() uint32 {
+	return e.code
+}
+
+type TCPUnexpectedCommandError struct {
+	want, got uint32
+}
+
+func (e TCPUnexpectedCommandError) Error() string {
+	return fmt.Sprintf("error FINS/TCP command: want %d, got: %d", e.want, e.got)
+}
+
+func TCPErrorCodeToMsg(u uint32) string {
+	if s, ok := tcpErrorCodeToMsg[u]; ok {
+		return s
+	}
+	return fmt.Sprintf("FINS/TCP error code: 0x%x: unknown", u)
+}
+
+var tcpErrorCodeToMsg = map[uint32]string{
+	0x00: "FINS/TCP error code 0x00: normal",
+	0x01: "FINS/TCP error code 0x01: the header is not 'FINS'",
+	0x02: "FINS/TCP error code 0x02: the data length is too long",
+	0x03: "FINS/TCP error code 0x03: the command is not supported",
+	0x20: "FINS/TCP error code 0x20: all connections are in use",
+	0x21: "FINS/TCP error code 0x21: the specified node is already connected",
+	0x22: "FINS/TCP error code 0x22: attempt to access a protected node from an unspecified IP address",
+	0x23: "FINS/TCP error code 0x23: the client FINS node address is out of range",
+	0x24: "FINS/TCP error code 0x24: the same FINS node address is being used by the client and server",
+	0x25: "FINS/TCP error code 0x25: all the node addresses available for allocation have been used",
+}
+
+// OperatingModeError end code 0x2201-0x2206, command is not executable in current operating mode
+type OperatingModeError struct {
+	EndCodeError
+}
+
+func (e OperatingModeError) Unwrap() error {
+	return e.EndCodeError
+}
+
+// AccessRightError end code 0x3001, another device holds the access right
+type AccessRightError struct {
+	EndCodeError
+	holder DeviceAddress
+}
+
+func (e AccessRightError) Error() string {
+	return fmt.Sprintf("%s, held by network %d node %d unit %d",
+		e.EndCodeError.Error(), e.holder.network, e.holder.node, e.holder.unit)
+}
+
+func (e AccessRightError) Unwrap() error {
+	return e.EndCodeError
+}
+
+// Holder address of the device holding the access right, zero if the PLC doesn't tell
+func (e AccessRightError) Holder() DeviceAddress {
+	return e.holder
+}
+
+// newEndCodeError returns typed error for end code families, EndCodeError for others
+func newEndCodeError(r *response) error {
+	e := EndCodeError{r.endCode}
+	if r.endCode >= EndCodeNotExecutableInCurrentModeNotPossibleDuringExecution &&
+		r.endCode <= EndCodeNotExecutableInCurrentModeWrongPLCModeInRun {
+		return OperatingModeError{e}
+	}
+	if r.endCode == EndCodeAccessWriteErrorNoAccessRight {
+		ae := AccessRightError{EndCodeError: e}
+		if len(r.data) >= 3 {
+			ae.holder = DeviceAddress{r.data[0], r.data[1], r.data[2]}
+		}
+		return ae
+	}
+	return e
+}
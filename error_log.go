@@ -0,0 +1,131 @@
+package fins
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+const (
+	// ErrorCodeAll error code for ClearError: clears all current errors
+	ErrorCodeAll uint16 = 0xffff
+
+	// errorLogReadMaxRecords max records read by one error log read command
+	errorLogReadMaxRecords = 20
+
+	// errorLogRecordSize error code(2) + details(2) + minute, second, day, hour, year, month in BCD
+	errorLogRecordSize = 10
+)
+
+// ErrorLogRecord a record of the PLC error log
+type ErrorLogRecord struct {
+	Code    uint16    // error code, like 0x4101 for FAL 101
+	Details uint16    // error details, meaning depends on Code
+	Time    time.Time // time the error occurred, in local time like ReadClock
+}
+
+// ReadErrorLog Reads count records of the PLC error log from record number start, 0 is the oldest one
+// records are read in pages of 20, reading stops at the last stored record
+func (c *Client) ReadErrorLog(start, count uint16) ([]ErrorLogRecord, error) {
+	return c.ReadErrorLogContext(context.Background(), start, count)
+}
+
+// ReadErrorLogContext same as ReadErrorLog, stops waiting for the response when ctx is done
+func (c *Client) ReadErrorLogContext(ctx context.Context, start, count uint16) ([]ErrorLogRecord, error) {
+	return wrapRead(c, func() ([]ErrorLogRecord, error) {
+		records := make([]ErrorLogRecord, 0, count)
+		for count > 0 {
+			n := count
+			if n > errorLogReadMaxRecords {
+				n = errorLogReadMaxRecords
+			}
+			page, stored, err := c.readErrorLog(ctx, start, n)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, page...)
+			start += uint16(len(page))
+			count -= uint16(len(page))
+			if len(page) == 0 || start >= stored {
+				break
+			}
+		}
+		return records, nil
+	})
+}
+
+// readErrorLog reads one page of records, returns the records and number of stored records
+func (c *Client) readErrorLog(ctx context.Context, start, count uint16) ([]ErrorLogRecord, uint16, error) {
+	r, err := c.sendCommandAndCheckResponse(ctx, errorLogReadCommand(start, count))
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(r.data) < 6 {
+		return nil, 0, ResponseLengthError{want: 6, got: len(r.data)}
+	}
+	stored := binary.BigEndian.Uint16(r.data[2:4])
+	n := int(binary.BigEndian.Uint16(r.data[4:6]))
+	if want := 6 + n*errorLogRecordSize; len(r.data) != want {
+		return nil, 0, ResponseLengthError{want: want, got: len(r.data)}
+	}
+	records := make([]ErrorLogRecord, n)
+	for i := range records {
+		records[i], err = decodeErrorLogRecord(r.data[6+i*errorLogRecordSize : 6+(i+1)*errorLogRecordSize])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return records, stored, nil
+}
+
+// ClearErrorLog Clears all records of the PLC error log
+func (c *Client) ClearErrorLog() error {
+	return c.ClearErrorLogContext(context.Background())
+}
+
+// ClearErrorLogContext same as ClearErrorLog, stops waiting for the response when ctx is done
+func (c *Client) ClearErrorLogContext(ctx context.Context) error {
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, errorLogClearCommand()))
+	})
+}
+
+// ClearError Clears the current error with the error code, ErrorCodeAll clears all current errors
+// the error log is not affected
+func (c *Client) ClearError(code uint16) error {
+	return c.ClearErrorContext(context.Background(), code)
+}
+
+// ClearErrorContext same as ClearError, stops waiting for the response when ctx is done
+func (c *Client) ClearErrorContext(ctx context.Context, code uint16) error {
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, errorClearCommand(code)))
+	})
+}
+
+func decodeErrorLogRecord(data []byte) (ErrorLogRecord, error) {
+	// minute, second, day, hour, year, month -> year, month, day, hour, minute, second
+	clock := []byte{data[8], data[9], data[6], data[7], data[4], data[5]}
+	t, err := decodeClock(clock)
+	if err != nil {
+		return ErrorLogRecord{}, fmt.Errorf("failed to decode error log record time: %w", err)
+	}
+	return ErrorLogRecord{
+		Code:    binary.BigEndian.Uint16(data[0:2]),
+		Details: binary.BigEndian.Uint16(data[2:4]),
+		Time:    *t,
+	}, nil
+}
+
+func encodeErrorLogRecord(r *ErrorLogRecord) ([]byte, error) {
+	clock, err := encodeClock(r.Time)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, errorLogRecordSize)
+	binary.BigEndian.PutUint16(data[0:2], r.Code)
+	binary.BigEndian.PutUint16(data[2:4], r.Details)
+	copy(data[4:], []byte{clock[4], clock[5], clock[2], clock[3], clock[0], clock[1]})
+	return data, nil
+}
@@ -0,0 +1,65 @@
+package fins
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ReadErrorLog(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	records, err := c.ReadErrorLog(0, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+
+	for i := uint16(0); i < 25; i++ {
+		s.LogError(0x4100+i, i)
+	}
+	records, err = c.ReadErrorLog(0, 100)
+	assert.Nil(t, err)
+	assert.Len(t, records, simulatorErrorLogSize)
+	assert.Equal(t, uint16(0x4105), records[0].Code, "the oldest records are dropped")
+	assert.Equal(t, uint16(5), records[0].Details)
+	assert.WithinDuration(t, time.Now(), records[0].Time, 2*time.Second)
+
+	records, err = c.ReadErrorLog(18, 5)
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, uint16(0x4118), records[1].Code)
+
+	status, err := c.ReadCPUStatus()
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(0x4118), status.ErrorCode)
+
+	assert.Nil(t, c.ClearError(0x4100))
+	status, _ = c.ReadCPUStatus()
+	assert.Equal(t, uint16(0x4118), status.ErrorCode, "not the current error")
+	assert.Nil(t, c.ClearError(ErrorCodeAll))
+	status, _ = c.ReadCPUStatus()
+	assert.Equal(t, uint16(0), status.ErrorCode)
+
+	assert.Nil(t, c.ClearErrorLog())
+	records, err = c.ReadErrorLog(0, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+}
+
+func Test_decodeErrorLogRecord(t *testing.T) {
+	// FAL 101 at 2024-10-17 08:30:05
+	data := []byte{0x41, 0x01, 0x00, 0x00, 0x30, 0x05, 0x17, 0x08, 0x24, 0x10}
+	record, err := decodeErrorLogRecord(data)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(0x4101), record.Code)
+	assert.Equal(t, "2024-10-17 08:30:05", record.Time.Format("2006-01-02 15:04:05"))
+
+	encoded, err := encodeErrorLogRecord(&record)
+	assert.Nil(t, err)
+	assert.Equal(t, data, encoded)
+
+	data[9] = 0x1A
+	_, err = decodeErrorLogRecord(data)
+	assert.ErrorAs(t, err, &BCDBadDigitError{})
+}
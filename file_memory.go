@@ -0,0 +1,376 @@
+package fins
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DiskMemoryCard disk number of the memory card
+	DiskMemoryCard uint16 = 0x8000
+
+	// DiskEMFileMemory disk number of EM file memory
+	DiskEMFileMemory uint16 = 0x8001
+)
+
+const (
+	// fileChunkSize max file data read or written by one command
+	fileChunkSize = 1000
+
+	// fileNameReadMaxFiles max file data read by one file name read command
+	fileNameReadMaxFiles = 20
+
+	// fileNameSize file names are 12 bytes like "FILENAME.EXT", name and extension are padded with spaces
+	fileNameSize = 12
+
+	// fileDataSize file name(12) + date/time(4) + file capacity(4)
+	fileDataSize = 20
+
+	// diskDataSize volume label(12) + date/time(4) + total capacity(4) + unused capacity(4) + total files(2)
+	diskDataSize = 26
+
+	// fileLastFlag bit 15 of number of files or data length, set in the response including the last file or data
+	fileLastFlag uint16 = 0x8000
+
+	// fileWriteNew single file write parameter: creates the file, fails if it exists
+	fileWriteNew uint16 = 0x0000
+
+	// fileWriteNewOverwrite single file write parameter: creates the file, overwrites it if it exists
+	fileWriteNewOverwrite uint16 = 0x0001
+
+	// fileWriteAppend single file write parameter: adds data to the end of the file
+	fileWriteAppend uint16 = 0x0002
+
+	// fileWriteOverwrite single file write parameter: overwrites data from file position
+	fileWriteOverwrite uint16 = 0x0003
+
+	// directoryCreate directory create/delete parameter: creates the directory
+	directoryCreate uint16 = 0x0000
+
+	// directoryDelete directory create/delete parameter: deletes the directory
+	directoryDelete uint16 = 0x0001
+)
+
+// FileInfo a file or directory in file memory, directories are listed with Size 0
+type FileInfo struct {
+	Name    string // like "DM.IOM"
+	Size    uint32
+	ModTime time.Time
+}
+
+// FileProgress called after each chunk of a file is transferred, done of total bytes are transferred
+type FileProgress func(done, total int)
+
+// ListFiles Reads names of files and directories in dir
+// paths of file memory are absolute paths separated by \ or /, like \BACKUP\DM.IOM. names are in 8.3 format
+func (c *Client) ListFiles(disk uint16, dir string) ([]FileInfo, error) {
+	return c.ListFilesContext(context.Background(), disk, dir)
+}
+
+// ListFilesContext same as ListFiles, stops waiting for the response when ctx is done
+func (c *Client) ListFilesContext(ctx context.Context, disk uint16, dir string) ([]FileInfo, error) {
+	dir, err := normalizeDirectory(dir)
+	if err != nil {
+		return nil, err
+	}
+	return wrapRead(c, func() ([]FileInfo, error) {
+		var files []FileInfo
+		for {
+			r, err := c.sendCommandAndCheckResponse(ctx, fileNameReadCommand(disk, uint16(len(files)), fileNameReadMaxFiles, dir))
+			if err != nil {
+				return nil, err
+			}
+			page, last, err := decodeFileNames(r.data)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, page...)
+			if last || len(page) == 0 {
+				return files, nil
+			}
+		}
+	})
+}
+
+// ReadFile Reads a file from file memory in chunks, progress can be nil
+func (c *Client) ReadFile(disk uint16, path string, progress FileProgress) ([]byte, error) {
+	return c.ReadFileContext(context.Background(), disk, path, progress)
+}
+
+// ReadFileContext same as ReadFile, stops waiting for the response when ctx is done
+func (c *Client) ReadFileContext(ctx context.Context, disk uint16, path string, progress FileProgress) ([]byte, error) {
+	dir, name, err := splitFilePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return wrapRead(c, func() ([]byte, error) {
+		var data []byte
+		for {
+			r, err := c.sendCommandAndCheckResponse(ctx, singleFileReadCommand(disk, name, uint32(len(data)), fileChunkSize, dir))
+			if err != nil {
+				return nil, err
+			}
+			size, chunk, last, err := decodeFileData(r.data, uint32(len(data)))
+			if err != nil {
+				return nil, err
+			}
+			if data == nil {
+				data = make([]byte, 0, size)
+			}
+			data = append(data, chunk...)
+			if progress != nil {
+				progress(len(data), int(size))
+			}
+			if last || len(chunk) == 0 {
+				if len(data) != int(size) {
+					return nil, ResponseLengthError{want: int(size), got: len(data)}
+				}
+				return data, nil
+			}
+		}
+	})
+}
+
+// WriteFile Writes data to a file in file memory in chunks, the file is overwritten if it exists. progress can be nil
+func (c *Client) WriteFile(disk uint16, path string, data []byte, progress FileProgress) error {
+	return c.WriteFileContext(context.Background(), disk, path, data, progress)
+}
+
+// WriteFileContext same as WriteFile, stops waiting for the response when ctx is done
+func (c *Client) WriteFileContext(ctx context.Context, disk uint16, path string, data []byte, progress FileProgress) error {
+	dir, name, err := splitFilePath(path)
+	if err != nil {
+		return err
+	}
+	return c.wrapOperate(func() error {
+		parameter := fileWriteNewOverwrite
+		for position := 0; ; {
+			end := position + fileChunkSize
+			if end > len(data) {
+				end = len(data)
+			}
+			command := singleFileWriteCommand(disk, parameter, name, uint32(position), dir, data[position:end])
+			if err := c.checkResponse(c.sendCommand(ctx, command)); err != nil {
+				return err
+			}
+			position, parameter = end, fileWriteAppend
+			if progress != nil {
+				progress(position, len(data))
+			}
+			if position == len(data) {
+				return nil
+			}
+		}
+	})
+}
+
+// DeleteFile Deletes a file from file memory
+func (c *Client) DeleteFile(disk uint16, path string) error {
+	return c.DeleteFileContext(context.Background(), disk, path)
+}
+
+// DeleteFileContext same as DeleteFile, stops waiting for the response when ctx is done
+func (c *Client) DeleteFileContext(ctx context.Context, disk uint16, path string) error {
+	dir, name, err := splitFilePath(path)
+	if err != nil {
+		return err
+	}
+	return c.wrapOperate(func() error {
+		r, err := c.sendCommandAndCheckResponse(ctx, fileDeleteCommand(disk, [][]byte{name}, dir))
+		if err != nil {
+			return err
+		}
+		if len(r.data) != 2 {
+			return ResponseLengthError{want: 2, got: len(r.data)}
+		}
+		return nil
+	})
+}
+
+// CopyFile Copies a file, srcDisk and dstDisk can be different
+func (c *Client) CopyFile(srcDisk uint16, srcPath string, dstDisk uint16, dstPath string) error {
+	return c.CopyFileContext(context.Background(), srcDisk, srcPath, dstDisk, dstPath)
+}
+
+// CopyFileContext same as CopyFile, stops waiting for the response when ctx is done
+func (c *Client) CopyFileContext(ctx context.Context, srcDisk uint16, srcPath string, dstDisk uint16, dstPath string) error {
+	srcDir, srcName, err := splitFilePath(srcPath)
+	if err != nil {
+		return err
+	}
+	dstDir, dstName, err := splitFilePath(dstPath)
+	if err != nil {
+		return err
+	}
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, fileCopyCommand(srcDisk, srcDir, srcName, dstDisk, dstDir, dstName)))
+	})
+}
+
+// RenameFile Changes the name of a file or directory, newName is a name in the same directory
+func (c *Client) RenameFile(disk uint16, path string, newName string) error {
+	return c.RenameFileContext(context.Background(), disk, path, newName)
+}
+
+// RenameFileContext same as RenameFile, stops waiting for the response when ctx is done
+func (c *Client) RenameFileContext(ctx context.Context, disk uint16, path string, newName string) error {
+	dir, oldName, err := splitFilePath(path)
+	if err != nil {
+		return err
+	}
+	name, err := encodeFileName(newName)
+	if err != nil {
+		return err
+	}
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, fileNameChangeCommand(disk, dir, oldName, name)))
+	})
+}
+
+// MakeDirectory Creates a directory, the parent directory must exist
+func (c *Client) MakeDirectory(disk uint16, path string) error {
+	return c.MakeDirectoryContext(context.Background(), disk, path)
+}
+
+// MakeDirectoryContext same as MakeDirectory, stops waiting for the response when ctx is done
+func (c *Client) MakeDirectoryContext(ctx context.Context, disk uint16, path string) error {
+	return c.directoryCreateDelete(ctx, disk, directoryCreate, path)
+}
+
+// RemoveDirectory Deletes an empty directory
+func (c *Client) RemoveDirectory(disk uint16, path string) error {
+	return c.RemoveDirectoryContext(context.Background(), disk, path)
+}
+
+// RemoveDirectoryContext same as RemoveDirectory, stops waiting for the response when ctx is done
+func (c *Client) RemoveDirectoryContext(ctx context.Context, disk uint16, path string) error {
+	return c.directoryCreateDelete(ctx, disk, directoryDelete, path)
+}
+
+func (c *Client) directoryCreateDelete(ctx context.Context, disk, parameter uint16, path string) error {
+	dir, name, err := splitFilePath(path)
+	if err != nil {
+		return err
+	}
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, directoryCreateDeleteCommand(disk, parameter, dir, name)))
+	})
+}
+
+// decodeFileNames decodes file name read response: disk data, number of files and file data
+func decodeFileNames(data []byte) ([]FileInfo, bool, error) {
+	if len(data) < diskDataSize+2 {
+		return nil, false, ResponseLengthError{want: diskDataSize + 2, got: len(data)}
+	}
+	n := binary.BigEndian.Uint16(data[diskDataSize : diskDataSize+2])
+	last := n&fileLastFlag != 0
+	n &^= fileLastFlag
+	data = data[diskDataSize+2:]
+	if len(data) != int(n)*fileDataSize {
+		return nil, false, ResponseLengthError{want: diskDataSize + 2 + int(n)*fileDataSize, got: diskDataSize + 2 + len(data)}
+	}
+	files := make([]FileInfo, n)
+	for i := range files {
+		fd := data[i*fileDataSize : (i+1)*fileDataSize]
+		files[i] = FileInfo{
+			Name:    decodeFileName(fd[0:12]),
+			ModTime: decodeFileTime(binary.BigEndian.Uint32(fd[12:16])),
+			Size:    binary.BigEndian.Uint32(fd[16:20]),
+		}
+	}
+	return files, last, nil
+}
+
+// decodeFileData decodes single file read response: file capacity, file position, data length and data
+func decodeFileData(data []byte, position uint32) (size uint32, chunk []byte, last bool, err error) {
+	if len(data) < 10 {
+		return 0, nil, false, ResponseLengthError{want: 10, got: len(data)}
+	}
+	size = binary.BigEndian.Uint32(data[0:4])
+	if got := binary.BigEndian.Uint32(data[4:8]); got != position {
+		return 0, nil, false, fmt.Errorf("failed to read file: want position %d, got: %d", position, got)
+	}
+	length := binary.BigEndian.Uint16(data[8:10])
+	last = length&fileLastFlag != 0
+	length &^= fileLastFlag
+	if len(data) != 10+int(length) {
+		return 0, nil, false, ResponseLengthError{want: 10 + int(length), got: len(data)}
+	}
+	return size, data[10:], last, nil
+}
+
+// normalizeDirectory returns upper case absolute directory path separated by \, root is \
+func normalizeDirectory(dir string) (string, error) {
+	names := strings.FieldsFunc(strings.ToUpper(dir), func(r rune) bool { return r == '\\' || r == '/' })
+	for _, name := range names {
+		if _, err := encodeFileName(name); err != nil {
+			return "", InvalidFilePathError{dir}
+		}
+	}
+	return `\` + strings.Join(names, `\`), nil
+}
+
+// splitFilePath splits path into normalized directory and encoded file name
+func splitFilePath(path string) (string, []byte, error) {
+	i := strings.LastIndexAny(path, `\/`)
+	dir, err := normalizeDirectory(path[:i+1])
+	if err != nil {
+		return "", nil, InvalidFilePathError{path}
+	}
+	name, err := encodeFileName(path[i+1:])
+	if err != nil {
+		return "", nil, InvalidFilePathError{path}
+	}
+	return dir, name, nil
+}
+
+// encodeFileName encodes name in 8.3 format to 12 bytes like "DM      .IOM"
+func encodeFileName(name string) ([]byte, error) {
+	name = strings.ToUpper(name)
+	base, ext, _ := strings.Cut(name, ".")
+	if base == "" || len(base) > 8 || len(ext) > 3 || strings.ContainsAny(ext, ".") {
+		return nil, InvalidFilePathError{name}
+	}
+	for _, r := range name {
+		if r <= ' ' || r > '~' || strings.ContainsRune(`\/:*?"<>|`, r) {
+			return nil, InvalidFilePathError{name}
+		}
+	}
+	return []byte(fmt.Sprintf("%-8s.%-3s", base, ext)), nil
+}
+
+func decodeFileName(b []byte) string {
+	base, ext, _ := strings.Cut(string(b), ".")
+	base, ext = strings.TrimRight(base, " \x00"), strings.TrimRight(ext, " \x00")
+	if ext == "" {
+		return base
+	}
+	return base + "." + ext
+}
+
+func encodeDirectory(dir string) []byte {
+	b := make([]byte, 2, 2+len(dir))
+	binary.BigEndian.PutUint16(b, uint16(len(dir)))
+	return append(b, dir...)
+}
+
+// encodeFileTime encodes t in MS-DOS format: year-1980(7 bits), month(4), day(5), hour(5), minute(6), second/2(5)
+func encodeFileTime(t time.Time) uint32 {
+	t = t.In(time.Local)
+	if t.Year() < 1980 {
+		return 0
+	}
+	return uint32(t.Year()-1980)<<25 | uint32(t.Month())<<21 | uint32(t.Day())<<16 |
+		uint32(t.Hour())<<11 | uint32(t.Minute())<<5 | uint32(t.Second()/2)
+}
+
+func decodeFileTime(v uint32) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Date(int(v>>25)+1980, time.Month(v>>21&0x0f), int(v>>16&0x1f),
+		int(v>>11&0x1f), int(v>>5&0x3f), int(v&0x1f)*2, 0, time.Local)
+}
@@ -0,0 +1,121 @@
+package fins
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_FileMemory(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	files, err := c.ListFiles(DiskMemoryCard, `\`)
+	assert.Nil(t, err)
+	assert.Empty(t, files)
+
+	assert.Nil(t, c.MakeDirectory(DiskMemoryCard, `\BACKUP`))
+	data := bytes.Repeat([]byte("0123456789"), 250)
+	var progress []int
+	err = c.WriteFile(DiskMemoryCard, "/backup/dm.iom", data, func(done, total int) {
+		assert.Equal(t, len(data), total)
+		progress = append(progress, done)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1000, 2000, 2500}, progress)
+
+	progress = nil
+	got, err := c.ReadFile(DiskMemoryCard, `\BACKUP\DM.IOM`, func(done, total int) {
+		progress = append(progress, done)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, data, got)
+	assert.Equal(t, []int{1000, 2000, 2500}, progress)
+
+	assert.Nil(t, c.CopyFile(DiskMemoryCard, `\BACKUP\DM.IOM`, DiskEMFileMemory, `\DM.IOM`))
+	assert.Nil(t, c.RenameFile(DiskMemoryCard, `\BACKUP\DM.IOM`, "DM0.IOM"))
+	assert.Nil(t, c.WriteFile(DiskMemoryCard, `\BACKUP\EMPTY`, nil, nil))
+
+	files, err = c.ListFiles(DiskMemoryCard, `\BACKUP`)
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, "DM0.IOM", files[0].Name)
+	assert.Equal(t, uint32(2500), files[0].Size)
+	assert.WithinDuration(t, time.Now(), files[0].ModTime, 3*time.Second)
+	assert.Equal(t, "EMPTY", files[1].Name)
+
+	got, err = c.ReadFile(DiskEMFileMemory, `\DM.IOM`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, data, got)
+	got, err = c.ReadFile(DiskMemoryCard, `\BACKUP\EMPTY`, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+
+	var endCodeErr EndCodeError
+	assert.True(t, errors.As(c.RemoveDirectory(DiskMemoryCard, `\BACKUP`), &endCodeErr), "directory is not empty")
+	assert.Nil(t, c.DeleteFile(DiskMemoryCard, `\BACKUP\DM0.IOM`))
+	assert.Nil(t, c.DeleteFile(DiskMemoryCard, `\BACKUP\EMPTY`))
+	assert.Nil(t, c.RemoveDirectory(DiskMemoryCard, `\BACKUP`))
+
+	_, err = c.ReadFile(DiskMemoryCard, `\BACKUP\DM0.IOM`, nil)
+	assert.Equal(t, EndCodeError{EndCodeReadNotPossibleFileMissing}, err)
+	assert.Equal(t, EndCodeError{EndCodeNoSuchDeviceFileDeviceMissing}, c.MakeDirectory(0x8002, `\A`))
+}
+
+func TestClient_ListFiles_pages(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	for i := 0; i < fileNameReadMaxFiles*2+5; i++ {
+		s.disks[DiskMemoryCard].files[joinFilePath(`\`, string(rune('A'+i/26))+string(rune('A'+i%26)))] = &simulatorFile{}
+	}
+	files, err := c.ListFiles(DiskMemoryCard, "")
+	assert.Nil(t, err)
+	assert.Len(t, files, fileNameReadMaxFiles*2+5)
+	assert.Equal(t, "AA", files[0].Name)
+	assert.Equal(t, "BS", files[len(files)-1].Name)
+}
+
+func Test_encodeFileName(t *testing.T) {
+	name, err := encodeFileName("dm.iom")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("DM      .IOM"), name)
+	assert.Equal(t, "DM.IOM", decodeFileName(name))
+
+	name, err = encodeFileName("BACKUP")
+	assert.Nil(t, err)
+	assert.Equal(t, "BACKUP", decodeFileName(name))
+
+	for _, bad := range []string{"", ".IOM", "TOOLONGNAME.IOM", "DM.IOMX", "A.B.C", "A B"} {
+		_, err = encodeFileName(bad)
+		assert.IsType(t, InvalidFilePathError{}, err, bad)
+	}
+
+	dir, name, err := splitFilePath("/Backup/2024/DM.IOM")
+	assert.Nil(t, err)
+	assert.Equal(t, `\BACKUP\2024`, dir)
+	assert.Equal(t, []byte("DM      .IOM"), name)
+	_, _, err = splitFilePath(`\BACKUP\`)
+	assert.IsType(t, InvalidFilePathError{}, err)
+}
+
+func Test_encodeFileTime(t *testing.T) {
+	tm := time.Date(2024, time.October, 17, 8, 30, 6, 0, time.Local)
+	assert.Equal(t, tm, decodeFileTime(encodeFileTime(tm)))
+	assert.True(t, decodeFileTime(0).IsZero())
+}
+
+func TestSimulator_cancelForcedKeepsFiles(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	data := []byte("0123456789")
+	assert.Nil(t, c.WriteFile(DiskMemoryCard, `\DM.IOM`, data, nil))
+	assert.Nil(t, c.CancelAllForced())
+	got, err := c.ReadFile(DiskMemoryCard, `\DM.IOM`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, data, got)
+}
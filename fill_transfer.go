@@ -0,0 +1,58 @@
+package fins
+
+import (
+	"context"
+)
+
+// FillWords Fills count words from address with value
+// Example:
+//
+//	FillWords(D, 100, 3, 0) will set D100=D101=D102=0
+func (c *Client) FillWords(memoryArea byte, address uint16, count uint16, value uint16) error {
+	return c.FillWordsContext(context.Background(), memoryArea, address, count, value)
+}
+
+// FillWordsContext same as FillWords, stops waiting for the response when ctx is done
+func (c *Client) FillWordsContext(ctx context.Context, memoryArea byte, address uint16, count uint16, value uint16) error {
+	return c.wrapOperate(func() error {
+		if err := checkIsWordMemoryArea(memoryArea); err != nil {
+			return err
+		}
+		addr, err := c.encodeMemoryAddress(memoryArea, address, 0)
+		if err != nil {
+			return err
+		}
+		command := fillCommand(addr, count, c.uint16sToBytes([]uint16{value}))
+		return c.checkResponse(c.sendCommand(ctx, command))
+	})
+}
+
+// TransferWords Copies count words from srcAddress to dstAddress inside the PLC
+// Example:
+//
+//	TransferWords(D, 100, H, 0, 2) will set H0=D100, H1=D101
+func (c *Client) TransferWords(srcMemoryArea byte, srcAddress uint16, dstMemoryArea byte, dstAddress uint16, count uint16) error {
+	return c.TransferWordsContext(context.Background(), srcMemoryArea, srcAddress, dstMemoryArea, dstAddress, count)
+}
+
+// TransferWordsContext same as TransferWords, stops waiting for the response when ctx is done
+func (c *Client) TransferWordsContext(ctx context.Context, srcMemoryArea byte, srcAddress uint16, dstMemoryArea byte, dstAddress uint16, count uint16) error {
+	return c.wrapOperate(func() error {
+		if err := checkIsWordMemoryArea(srcMemoryArea); err != nil {
+			return err
+		}
+		if err := checkIsWordMemoryArea(dstMemoryArea); err != nil {
+			return err
+		}
+		src, err := c.encodeMemoryAddress(srcMemoryArea, srcAddress, 0)
+		if err != nil {
+			return err
+		}
+		dst, err := c.encodeMemoryAddress(dstMemoryArea, dstAddress, 0)
+		if err != nil {
+			return err
+		}
+		command := transferCommand(src, dst, count)
+		return c.checkResponse(c.sendCommand(ctx, command))
+	})
+}
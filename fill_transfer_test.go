@@ -0,0 +1,34 @@
+package fins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_FillWordsAndTransferWords(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	err := c.FillWords(MemoryAreaDMWord, 100, 1000, 0x1234)
+	assert.Nil(t, err)
+	vals, err := c.ReadWords(MemoryAreaDMWord, 99, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0, 0x1234, 0x1234}, vals)
+	vals, err = c.ReadWords(MemoryAreaDMWord, 1098, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0x1234, 0x1234, 0}, vals)
+
+	assert.Nil(t, c.WriteWords(MemoryAreaDMWord, 0, []uint16{1, 2, 3}))
+	err = c.TransferWords(MemoryAreaDMWord, 0, MemoryAreaHRWord, 10, 3)
+	assert.Nil(t, err)
+	vals, err = c.ReadWords(MemoryAreaHRWord, 10, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{1, 2, 3}, vals)
+
+	err = c.FillWords(MemoryAreaDMWord, DmAreaSize-1, 2, 0)
+	assert.Equal(t, EndCodeError{EndCodeAddressRangeExceeded}, err)
+
+	err = c.TransferWords(MemoryAreaDMBit, 0, MemoryAreaHRWord, 10, 3)
+	assert.Equal(t, IncompatibleMemoryAreaError{MemoryAreaDMBit}, err)
+}
@@ -0,0 +1,90 @@
+package fins
+
+import (
+	"context"
+)
+
+const (
+	// ForceSpecReset forced set/reset specification: forced reset
+	ForceSpecReset uint16 = 0x0000
+
+	// ForceSpecSet forced set/reset specification: forced set
+	ForceSpecSet uint16 = 0x0001
+
+	// ForceSpecReleaseOff forced set/reset specification: releases the forced status and turns the bit OFF
+	ForceSpecReleaseOff uint16 = 0x8000
+
+	// ForceSpecReleaseOn forced set/reset specification: releases the forced status and turns the bit ON
+	ForceSpecReleaseOn uint16 = 0x8001
+
+	// ForceSpecRelease forced set/reset specification: releases the forced status and keeps the bit status
+	ForceSpecRelease uint16 = 0xffff
+)
+
+// ForcedBit a bit forced by ForceSetResetMultiple
+// MemoryArea can be MemoryAreaCIOBit, MemoryAreaWRBit, MemoryAreaHRBit or MemoryAreaTimerCounterCompletionFlag
+type ForcedBit struct {
+	Spec       uint16 // ForceSpecSet, ForceSpecReset...
+	MemoryArea byte
+	Address    uint16
+	BitOffset  byte
+}
+
+// ForceSet Forces a bit ON, the bit keeps ON until released whatever the program or writes do
+func (c *Client) ForceSet(memoryArea byte, address uint16, bitOffset byte) error {
+	return c.ForceSetContext(context.Background(), memoryArea, address, bitOffset)
+}
+
+// ForceSetContext same as ForceSet, stops waiting for the response when ctx is done
+func (c *Client) ForceSetContext(ctx context.Context, memoryArea byte, address uint16, bitOffset byte) error {
+	return c.ForceSetResetMultipleContext(ctx, []ForcedBit{{ForceSpecSet, memoryArea, address, bitOffset}})
+}
+
+// ForceReset Forces a bit OFF, the bit keeps OFF until released whatever the program or writes do
+func (c *Client) ForceReset(memoryArea byte, address uint16, bitOffset byte) error {
+	return c.ForceResetContext(context.Background(), memoryArea, address, bitOffset)
+}
+
+// ForceResetContext same as ForceReset, stops waiting for the response when ctx is done
+func (c *Client) ForceResetContext(ctx context.Context, memoryArea byte, address uint16, bitOffset byte) error {
+	return c.ForceSetResetMultipleContext(ctx, []ForcedBit{{ForceSpecReset, memoryArea, address, bitOffset}})
+}
+
+// ForceSetResetMultiple Forces or releases bits with one command
+// the PLC must be in PROGRAM or MONITOR mode
+func (c *Client) ForceSetResetMultiple(bits []ForcedBit) error {
+	return c.ForceSetResetMultipleContext(context.Background(), bits)
+}
+
+// ForceSetResetMultipleContext same as ForceSetResetMultiple, stops waiting for the response when ctx is done
+func (c *Client) ForceSetResetMultipleContext(ctx context.Context, bits []ForcedBit) error {
+	if len(bits) == 0 {
+		return EmptyWriteRequestError{}
+	}
+	encoded := make([]ForcedBit, len(bits))
+	for i, bit := range bits {
+		if err := checkIsForceableMemoryArea(bit.MemoryArea); err != nil {
+			return err
+		}
+		addr, err := c.encodeMemoryAddress(bit.MemoryArea, bit.Address, bit.BitOffset)
+		if err != nil {
+			return err
+		}
+		encoded[i] = ForcedBit{bit.Spec, addr.memoryArea, addr.address, addr.bitOffset}
+	}
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, forcedSetResetCommand(encoded)))
+	})
+}
+
+// CancelAllForced Releases all forced bits, they keep their current status
+func (c *Client) CancelAllForced() error {
+	return c.CancelAllForcedContext(context.Background())
+}
+
+// CancelAllForcedContext same as CancelAllForced, stops waiting for the response when ctx is done
+func (c *Client) CancelAllForcedContext(ctx context.Context) error {
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, forcedSetResetCancelCommand()))
+	})
+}
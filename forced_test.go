@@ -0,0 +1,50 @@
+package fins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ForceSetReset(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	assert.Nil(t, c.ForceSet(MemoryAreaWRBit, 10, 3))
+	assert.Nil(t, c.ForceReset(MemoryAreaHRBit, 20, 0))
+	assert.Nil(t, c.WriteWords(MemoryAreaHRWord, 20, []uint16{0xffff}))
+	assert.Nil(t, c.WriteBits(MemoryAreaWRBit, 10, 2, []bool{true, false, true}))
+
+	bits, err := c.ReadBits(MemoryAreaWRBit, 10, 2, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, true, true}, bits, "forced bit is not overwritten")
+	words, err := c.ReadWords(MemoryAreaHRWord, 20, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0xfffe}, words)
+
+	assert.Nil(t, c.ForceSetResetMultiple([]ForcedBit{
+		{ForceSpecReleaseOff, MemoryAreaWRBit, 10, 3},
+		{ForceSpecSet, MemoryAreaCIOBit, 0, 5},
+		{ForceSpecSet, MemoryAreaTimerCounterCompletionFlag, 7, 0},
+	}))
+	bits, _ = c.ReadBits(MemoryAreaWRBit, 10, 3, 1)
+	assert.Equal(t, []bool{false}, bits)
+	assert.Len(t, s.forced, 3)
+
+	assert.Nil(t, c.CancelAllForced())
+	assert.Nil(t, c.WriteWords(MemoryAreaHRWord, 20, []uint16{0xffff}))
+	words, _ = c.ReadWords(MemoryAreaHRWord, 20, 1)
+	assert.Equal(t, []uint16{0xffff}, words)
+}
+
+func TestClient_ForceSetResetMultiple_error(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	assert.Equal(t, EmptyWriteRequestError{}, c.ForceSetResetMultiple(nil))
+	assert.Equal(t, IncompatibleMemoryAreaError{MemoryAreaDMBit}, c.ForceSet(MemoryAreaDMBit, 0, 0))
+	assert.Equal(t, EndCodeError{EndCodeParameterError}, c.ForceSetResetMultiple([]ForcedBit{{0x1234, MemoryAreaWRBit, 0, 0}}))
+
+	assert.Nil(t, c.Run(OperatingModeRun, ProgramNumberAll))
+	assert.ErrorAs(t, c.ForceSet(MemoryAreaWRBit, 0, 0), &OperatingModeError{})
+}
@@ -0,0 +1,231 @@
+package fins
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Address memory address in CS/CJ mode, usually made by ParseAddress from Omron notation
+// MemoryArea is the bit area code for addresses with .bit suffix, the word area code for others.
+// words and bits at an address are read and written by ReadWordsAt, WriteWordsAt, ReadBitsAt and WriteBitsAt,
+// typed values by ReadStruct and WriteStruct with the address in fins tag, other methods take its fields:
+//
+//	c.ReadFloat32s(a.MemoryArea, a.Address, 2)
+//	c.ReadMultiple([]MultipleReadItem{MultipleReadItem(a)})
+type Address struct {
+	MemoryArea byte
+	Address    uint16
+	BitOffset  byte
+}
+
+// addressNotation prefix of Omron notation, address n is stored at offset+n of memory area
+type addressNotation struct {
+	prefix        string
+	area, bitArea byte   // memory area without and with .bit suffix
+	bitSuffix     bool   // .bit suffix is allowed
+	offset, max   uint16 // max address in notation
+}
+
+var addressNotations = []addressNotation{
+	{"CIO", MemoryAreaCIOWord, MemoryAreaCIOBit, true, 0, 0xffff},
+	{"W", MemoryAreaWRWord, MemoryAreaWRBit, true, 0, 0xffff},
+	{"H", MemoryAreaHRWord, MemoryAreaHRBit, true, 0, 0xffff},
+	{"A", MemoryAreaARWord, MemoryAreaARBit, true, 0, 0xffff},
+	{"D", MemoryAreaDMWord, MemoryAreaDMBit, true, 0, 0xffff},
+	{"E", MemoryAreaEMCurrentBankWord, MemoryAreaEMCurrentBankBit, true, 0, 0xffff},
+	{"TF", MemoryAreaTimerCounterCompletionFlag, MemoryAreaTimerCounterCompletionFlag, false, 0, 0x0fff},
+	{"CF", MemoryAreaTimerCounterCompletionFlag, MemoryAreaTimerCounterCompletionFlag, false, 0x8000, 0x0fff},
+	{"T", MemoryAreaTimerCounterPV, MemoryAreaTimerCounterCompletionFlag, false, 0, 0x0fff},
+	{"C", MemoryAreaTimerCounterPV, MemoryAreaTimerCounterCompletionFlag, false, 0x8000, 0x0fff},
+	{"TK", MemoryAreaTaskBit, MemoryAreaTaskBit, false, 0, 0xffff},
+	{"IR", MemoryAreaIndexRegisterPV, MemoryAreaIndexRegisterPV, false, 0, 15},
+	{"DR", MemoryAreaDataRegisterPV, MemoryAreaDataRegisterPV, false, 0, 15},
+}
+
+// ParseAddress Parses an address in Omron notation
+// Example:
+//
+//	D100, W3, H10, A200, CIO10 or 10: word of DM, work, holding, auxiliary and CIO area
+//	D100.05, CIO0.05 or 0.05: bit 5 of the word
+//	E3_200, E3_200.01: word and bit of EM bank 3, E200 is word of the current EM bank
+//	T10, C10: timer and counter, PV for word access and completion flag for bit access
+//	TF10, CF10: completion flag of timer and counter
+//	IR0, DR0, TK0: index register, data register and task flag
+//	0x07:1.00: memory area code, address and bit, String of areas without Omron notation
+func ParseAddress(s string) (Address, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	word, bit, hasBit := strings.Cut(str, ".")
+	if strings.HasPrefix(word, "0X") {
+		return parseRawAddress(s, word[2:], bit, hasBit)
+	}
+
+	notation, _ := findAddressNotation("CIO")
+	number, bank := word, -1
+	if prefix, n, ok := strings.Cut(word, "_"); ok && strings.HasPrefix(prefix, "E") {
+		b, err := strconv.ParseUint(prefix[1:], 16, 8)
+		if err != nil || b > uint64(MemoryAreaEMCWord-MemoryAreaEM0Word) {
+			return Address{}, InvalidAddressError{s}
+		}
+		notation, _ = findAddressNotation("E")
+		number, bank = n, int(b)
+	} else if i := strings.IndexAny(word, "0123456789"); i > 0 {
+		if notation, ok = findAddressNotation(word[:i]); !ok {
+			return Address{}, InvalidAddressError{s}
+		}
+		number = word[i:]
+	}
+
+	n, err := strconv.ParseUint(number, 10, 16)
+	if err != nil || n > uint64(notation.max) {
+		return Address{}, InvalidAddressError{s}
+	}
+	a := Address{MemoryArea: notation.area, Address: notation.offset + uint16(n)}
+	if hasBit {
+		offset, err := strconv.ParseUint(bit, 10, 8)
+		if !notation.bitSuffix || err != nil || len(bit) > 2 || offset > 15 {
+			return Address{}, InvalidAddressError{s}
+		}
+		a.MemoryArea, a.BitOffset = notation.bitArea, byte(offset)
+	}
+	if bank >= 0 {
+		a.MemoryArea = MemoryAreaEM0Word + byte(bank)
+		if hasBit {
+			a.MemoryArea = MemoryAreaEM0Bit + byte(bank)
+		}
+	}
+	return a, nil
+}
+
+// parseRawAddress parses address like 0x07:1.00, word is 07:1
+func parseRawAddress(s, word, bit string, hasBit bool) (Address, error) {
+	area, number, ok := strings.Cut(word, ":")
+	code, err1 := strconv.ParseUint(area, 16, 8)
+	n, err2 := strconv.ParseUint(number, 10, 16)
+	if !ok || err1 != nil || err2 != nil {
+		return Address{}, InvalidAddressError{s}
+	}
+	a := Address{MemoryArea: byte(code), Address: uint16(n)}
+	if hasBit {
+		offset, err := strconv.ParseUint(bit, 10, 8)
+		if err != nil || len(bit) > 2 || offset > 15 {
+			return Address{}, InvalidAddressError{s}
+		}
+		a.BitOffset = byte(offset)
+	}
+	return a, nil
+}
+
+func findAddressNotation(prefix string) (addressNotation, bool) {
+	for _, an := range addressNotations {
+		if an.prefix == prefix {
+			return an, true
+		}
+	}
+	return addressNotation{}, false
+}
+
+// MustParseAddress same as ParseAddress, panics if s is invalid
+func MustParseAddress(s string) Address {
+	a, err := ParseAddress(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// String address in canonical Omron notation, like 0x07:1.00 for areas without notation, ParseAddress(a.String()) returns a
+func (a Address) String() string {
+	switch {
+	case a.MemoryArea >= MemoryAreaEM0Word && a.MemoryArea <= MemoryAreaEMCWord:
+		return fmt.Sprintf("E%X_%d", a.MemoryArea-MemoryAreaEM0Word, a.Address)
+	case a.MemoryArea >= MemoryAreaEM0Bit && a.MemoryArea <= MemoryAreaEMCBit:
+		return fmt.Sprintf("E%X_%d.%02d", a.MemoryArea-MemoryAreaEM0Bit, a.Address, a.BitOffset)
+	}
+	for _, an := range addressNotations {
+		if a.MemoryArea != an.area && a.MemoryArea != an.bitArea ||
+			a.Address < an.offset || a.Address-an.offset > an.max {
+			continue
+		}
+		if an.bitSuffix && a.MemoryArea == an.bitArea {
+			return fmt.Sprintf("%s%d.%02d", an.prefix, a.Address-an.offset, a.BitOffset)
+		}
+		return fmt.Sprintf("%s%d", an.prefix, a.Address-an.offset)
+	}
+	return fmt.Sprintf("0x%02X:%d.%02d", a.MemoryArea, a.Address, a.BitOffset)
+}
+
+// bitMemoryArea memory area for bit access, bits of a word address start from bit 0 of the word
+func (a Address) bitMemoryArea() byte {
+	if a.MemoryArea >= MemoryAreaEM0Word && a.MemoryArea <= MemoryAreaEMCWord {
+		return a.MemoryArea - MemoryAreaEM0Word + MemoryAreaEM0Bit
+	}
+	for _, an := range addressNotations {
+		if a.MemoryArea == an.area {
+			return an.bitArea
+		}
+	}
+	return a.MemoryArea
+}
+
+// bitWordMemoryArea word area storing bits of a bit area, false for flag areas like timer/counter completion flags
+func bitWordMemoryArea(bitArea byte) (byte, bool) {
+	if bitArea >= MemoryAreaEM0Bit && bitArea <= MemoryAreaEMCBit {
+		return bitArea - MemoryAreaEM0Bit + MemoryAreaEM0Word, true
+	}
+	for _, an := range addressNotations {
+		if an.bitSuffix && an.bitArea == bitArea {
+			return an.area, true
+		}
+	}
+	return 0, false
+}
+
+// checkAddressRange returns AddressOverflowError if count items from address go past address 65535,
+// bits of word areas take 16 per address
+func checkAddressRange(memoryArea byte, address uint16, bitOffset byte, count int) error {
+	if count == 0 {
+		return nil
+	}
+	last := int(address) + count - 1
+	if _, ok := bitWordMemoryArea(memoryArea); ok {
+		last = (int(address)*16 + int(bitOffset) + count - 1) / 16
+	}
+	if last > 0xffff {
+		return AddressOverflowError{memoryArea, address, count}
+	}
+	return nil
+}
+
+// bitChunkAddress address and bit offset of the n-th bit from address.bitOffset, flags like timer/counter
+// completion flags take one address each
+func bitChunkAddress(bitArea byte, address uint16, bitOffset byte, n int) (uint16, byte) {
+	if _, ok := bitWordMemoryArea(bitArea); !ok {
+		return address + uint16(n), bitOffset
+	}
+	pos := int(address)*16 + int(bitOffset) + n
+	return uint16(pos / 16), byte(pos % 16)
+}
+
+// ReadWordsAt Reads words from address a, stops waiting for the response when ctx is done
+func (c *Client) ReadWordsAt(ctx context.Context, a Address, readCount uint16) ([]uint16, error) {
+	return c.ReadWordsContext(ctx, a.MemoryArea, a.Address, readCount)
+}
+
+// WriteWordsAt Writes words to address a, stops waiting for the response when ctx is done
+func (c *Client) WriteWordsAt(ctx context.Context, a Address, data []uint16) error {
+	return c.WriteWordsContext(ctx, a.MemoryArea, a.Address, data)
+}
+
+// ReadBitsAt Reads bits from address a, from bit 0 if a is a word address like D100,
+// T10 and C10 read completion flags. stops waiting for the response when ctx is done
+func (c *Client) ReadBitsAt(ctx context.Context, a Address, readCount uint16) ([]bool, error) {
+	return c.ReadBitsContext(ctx, a.bitMemoryArea(), a.Address, a.BitOffset, readCount)
+}
+
+// WriteBitsAt Writes bits to address a, from bit 0 if a is a word address like D100,
+// T10 and C10 write completion flags. stops waiting for the response when ctx is done
+func (c *Client) WriteBitsAt(ctx context.Context, a Address, data []bool) error {
+	return c.WriteBitsContext(ctx, a.bitMemoryArea(), a.Address, a.BitOffset, data)
+}
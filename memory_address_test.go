@@ -0,0 +1,106 @@
+package fins
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAddress(t *testing.T) {
+	for _, tt := range []struct {
+		s, canonical string
+		want         Address
+	}{
+		{"D100", "D100", Address{MemoryAreaDMWord, 100, 0}},
+		{"d100.5", "D100.05", Address{MemoryAreaDMBit, 100, 5}},
+		{"CIO0.05", "CIO0.05", Address{MemoryAreaCIOBit, 0, 5}},
+		{"10", "CIO10", Address{MemoryAreaCIOWord, 10, 0}},
+		{"0.15", "CIO0.15", Address{MemoryAreaCIOBit, 0, 15}},
+		{"W3", "W3", Address{MemoryAreaWRWord, 3, 0}},
+		{" H10.01 ", "H10.01", Address{MemoryAreaHRBit, 10, 1}},
+		{"A200", "A200", Address{MemoryAreaARWord, 200, 0}},
+		{"E3_200", "E3_200", Address{MemoryAreaEM3Word, 200, 0}},
+		{"EC_200.01", "EC_200.01", Address{MemoryAreaEMCBit, 200, 1}},
+		{"E200", "E200", Address{MemoryAreaEMCurrentBankWord, 200, 0}},
+		{"T10", "T10", Address{MemoryAreaTimerCounterPV, 10, 0}},
+		{"C10", "C10", Address{MemoryAreaTimerCounterPV, 0x800a, 0}},
+		{"IR3", "IR3", Address{MemoryAreaIndexRegisterPV, 3, 0}},
+		{"DR15", "DR15", Address{MemoryAreaDataRegisterPV, 15, 0}},
+		{"TK2", "TK2", Address{MemoryAreaTaskBit, 2, 0}},
+		{"TF10", "TF10", Address{MemoryAreaTimerCounterCompletionFlag, 10, 0}},
+		{"cf10", "CF10", Address{MemoryAreaTimerCounterCompletionFlag, 0x800a, 0}},
+		{"0x07:1.03", "0x07:1.03", Address{MemoryAreaClockPulsesConditionFlagsBit, 1, 3}},
+		{"0x0a0:2", "E0_2", Address{MemoryAreaEM0Word, 2, 0}},
+	} {
+		a, err := ParseAddress(tt.s)
+		assert.Nil(t, err, tt.s)
+		assert.Equal(t, tt.want, a, tt.s)
+		assert.Equal(t, tt.canonical, a.String(), tt.s)
+		again, err := ParseAddress(a.String())
+		assert.Nil(t, err, tt.s)
+		assert.Equal(t, a, again, tt.s)
+	}
+
+	for _, s := range []string{
+		"", "D", "X100", "D100.16", "D100.005", "D-1", "D65536", "T4096", "C10.01",
+		"IR16", "ED_100", "E3_", "D100.", "D_100", "TF4096", "0x", "0x07", "0x100:0", "0x07:1.16",
+	} {
+		_, err := ParseAddress(s)
+		assert.Equal(t, InvalidAddressError{s}, err, s)
+	}
+	assert.Panics(t, func() { MustParseAddress("X") })
+}
+
+func TestAddress_StringRoundTrip(t *testing.T) {
+	for area := range memoryAreaItemSize {
+		var bitOffset byte
+		if _, ok := bitWordMemoryArea(area); ok {
+			bitOffset = 5
+		}
+		for _, address := range []uint16{0, 10, 0x8003, 0xffff} {
+			a := Address{area, address, bitOffset}
+			again, err := ParseAddress(a.String())
+			assert.Nil(t, err, a.String())
+			assert.Equal(t, a, again, a.String())
+		}
+	}
+}
+
+func TestClient_At(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+	ctx := context.Background()
+
+	assert.Nil(t, c.WriteWordsAt(ctx, MustParseAddress("D100"), []uint16{0x0005}))
+	words, err := c.ReadWordsAt(ctx, MustParseAddress("D100"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0x0005}, words)
+
+	// bits of a word address start from bit 0
+	bits, err := c.ReadBitsAt(ctx, MustParseAddress("D100"), 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false, true}, bits)
+	assert.Nil(t, c.WriteBitsAt(ctx, MustParseAddress("D100.01"), []bool{true, false}))
+	assert.Nil(t, c.WriteBitsAt(ctx, MustParseAddress("D100.08"), []bool{true}))
+	words, err = c.ReadWordsAt(ctx, MustParseAddress("D100"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0x0103}, words)
+
+	s.write(memAddr(MemoryAreaTimerCounterCompletionFlag, 0x8003), 1, []byte{1})
+	bits, err = c.ReadBitsAt(ctx, MustParseAddress("C3"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true}, bits)
+	assert.Nil(t, c.WriteBitsAt(ctx, MustParseAddress("C3"), []bool{false}))
+	bits, err = c.ReadBitsAt(ctx, MustParseAddress("CF3"), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false}, bits)
+
+	// other methods take fields of Address
+	a := MustParseAddress("W10")
+	assert.Nil(t, c.WriteInt16s(a.MemoryArea, a.Address, []int16{-2}))
+	values, err := c.ReadMultiple([]MultipleReadItem{MultipleReadItem(a), MultipleReadItem(MustParseAddress("D100.08"))})
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(0xfffe), values[0].Word)
+	assert.Equal(t, true, values[1].Bit)
+}
@@ -1,6 +1,6 @@
 package fins
 
-// fixme  only support DM AR AR WR for now
+// memory area codes of CS/CJ mode, used by CS, CJ, CP and NJ series CPU units
 
 const (
 	// MemoryAreaCIOBit Memory area: CIO area; bit
@@ -39,18 +39,139 @@ const (
 	// MemoryAreaDMWord Memory area: data area; word
 	MemoryAreaDMWord byte = 0x82
 
+	// MemoryAreaEMCurrentBankBit Memory area: EM area current bank; bit
+	MemoryAreaEMCurrentBankBit byte = 0x0a
+
+	// MemoryAreaEMCurrentBankWord Memory area: EM area current bank; word
+	MemoryAreaEMCurrentBankWord byte = 0x98
+
+	// MemoryAreaEM0Bit Memory area: EM area bank 0; bit
+	MemoryAreaEM0Bit byte = 0x20
+
+	// MemoryAreaEM1Bit Memory area: EM area bank 1; bit
+	MemoryAreaEM1Bit byte = 0x21
+
+	// MemoryAreaEM2Bit Memory area: EM area bank 2; bit
+	MemoryAreaEM2Bit byte = 0x22
+
+	// MemoryAreaEM3Bit Memory area: EM area bank 3; bit
+	MemoryAreaEM3Bit byte = 0x23
+
+	// MemoryAreaEM4Bit Memory area: EM area bank 4; bit
+	MemoryAreaEM4Bit byte = 0x24
+
+	// MemoryAreaEM5Bit Memory area: EM area bank 5; bit
+	MemoryAreaEM5Bit byte = 0x25
+
+	// MemoryAreaEM6Bit Memory area: EM area bank 6; bit
+	MemoryAreaEM6Bit byte = 0x26
+
+	// MemoryAreaEM7Bit Memory area: EM area bank 7; bit
+	MemoryAreaEM7Bit byte = 0x27
+
+	// MemoryAreaEM8Bit Memory area: EM area bank 8; bit
+	MemoryAreaEM8Bit byte = 0x28
+
+	// MemoryAreaEM9Bit Memory area: EM area bank 9; bit
+	MemoryAreaEM9Bit byte = 0x29
+
+	// MemoryAreaEMABit Memory area: EM area bank 10; bit
+	MemoryAreaEMABit byte = 0x2a
+
+	// MemoryAreaEMBBit Memory area: EM area bank 11; bit
+	MemoryAreaEMBBit byte = 0x2b
+
+	// MemoryAreaEMCBit Memory area: EM area bank 12; bit
+	MemoryAreaEMCBit byte = 0x2c
+
+	// MemoryAreaEM0Word Memory area: EM area bank 0; word
+	MemoryAreaEM0Word byte = 0xa0
+
+	// MemoryAreaEM1Word Memory area: EM area bank 1; word
+	MemoryAreaEM1Word byte = 0xa1
+
+	// MemoryAreaEM2Word Memory area: EM area bank 2; word
+	MemoryAreaEM2Word byte = 0xa2
+
+	// MemoryAreaEM3Word Memory area: EM area bank 3; word
+	MemoryAreaEM3Word byte = 0xa3
+
+	// MemoryAreaEM4Word Memory area: EM area bank 4; word
+	MemoryAreaEM4Word byte = 0xa4
+
+	// MemoryAreaEM5Word Memory area: EM area bank 5; word
+	MemoryAreaEM5Word byte = 0xa5
+
+	// MemoryAreaEM6Word Memory area: EM area bank 6; word
+	MemoryAreaEM6Word byte = 0xa6
+
+	// MemoryAreaEM7Word Memory area: EM area bank 7; word
+	MemoryAreaEM7Word byte = 0xa7
+
+	// MemoryAreaEM8Word Memory area: EM area bank 8; word
+	MemoryAreaEM8Word byte = 0xa8
+
+	// MemoryAreaEM9Word Memory area: EM area bank 9; word
+	MemoryAreaEM9Word byte = 0xa9
+
+	// MemoryAreaEMAWord Memory area: EM area bank 10; word
+	MemoryAreaEMAWord byte = 0xaa
+
+	// MemoryAreaEMBWord Memory area: EM area bank 11; word
+	MemoryAreaEMBWord byte = 0xab
+
+	// MemoryAreaEMCWord Memory area: EM area bank 12; word
+	MemoryAreaEMCWord byte = 0xac
+
 	// MemoryAreaTaskBit Memory area: task flags; bit
 	MemoryAreaTaskBit byte = 0x06
 
 	// MemoryAreaTaskStatus Memory area: task flags; status
 	MemoryAreaTaskStatus byte = 0x46
 
-	// MemoryAreaIndexRegisterPV Memory area: CIO bit
+	// MemoryAreaIndexRegisterPV Memory area: index register PV; double word
 	MemoryAreaIndexRegisterPV byte = 0xdc
 
-	// MemoryAreaDataRegisterPV Memory area: CIO bit
+	// MemoryAreaDataRegisterPV Memory area: data register PV; word
 	MemoryAreaDataRegisterPV byte = 0xbc
 
-	// MemoryAreaClockPulsesConditionFlagsBit Memory area: CIO bit
+	// MemoryAreaClockPulsesConditionFlagsBit Memory area: clock pulses and condition flags; bit
 	MemoryAreaClockPulsesConditionFlagsBit byte = 0x07
 )
+
+// memoryAreaItemSize bytes of each item of a memory area: 1 for bits and flags, 2 for words, 4 for double words
+var memoryAreaItemSize = map[byte]int{
+	MemoryAreaCIOBit:                       1,
+	MemoryAreaWRBit:                        1,
+	MemoryAreaHRBit:                        1,
+	MemoryAreaARBit:                        1,
+	MemoryAreaDMBit:                        1,
+	MemoryAreaEMCurrentBankBit:             1,
+	MemoryAreaTimerCounterCompletionFlag:   1,
+	MemoryAreaTaskBit:                      1,
+	MemoryAreaTaskStatus:                   1,
+	MemoryAreaClockPulsesConditionFlagsBit: 1,
+	MemoryAreaCIOWord:                      2,
+	MemoryAreaWRWord:                       2,
+	MemoryAreaHRWord:                       2,
+	MemoryAreaARWord:                       2,
+	MemoryAreaDMWord:                       2,
+	MemoryAreaEMCurrentBankWord:            2,
+	MemoryAreaTimerCounterPV:               2,
+	MemoryAreaDataRegisterPV:               2,
+	MemoryAreaIndexRegisterPV:              4,
+}
+
+// memoryAreaReadOnly memory areas can't be written by memory area write
+var memoryAreaReadOnly = map[byte]struct{}{
+	MemoryAreaTaskBit:                      {},
+	MemoryAreaTaskStatus:                   {},
+	MemoryAreaClockPulsesConditionFlagsBit: {},
+}
+
+func init() {
+	for bank := byte(0); bank <= 0x0c; bank++ {
+		memoryAreaItemSize[MemoryAreaEM0Bit+bank] = 1
+		memoryAreaItemSize[MemoryAreaEM0Word+bank] = 2
+	}
+}
@@ -0,0 +1,88 @@
+package fins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_wordAreas(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	for _, area := range []byte{
+		MemoryAreaCIOWord, MemoryAreaWRWord, MemoryAreaHRWord, MemoryAreaARWord, MemoryAreaDMWord,
+		MemoryAreaTimerCounterPV, MemoryAreaDataRegisterPV, MemoryAreaEM0Word, MemoryAreaEMCWord,
+	} {
+		assert.Nil(t, c.WriteWords(area, 2, []uint16{0x1234, 0x5678}), "area 0x%02X", area)
+		words, err := c.ReadWords(area, 2, 2)
+		assert.Nil(t, err, "area 0x%02X", area)
+		assert.Equal(t, []uint16{0x1234, 0x5678}, words, "area 0x%02X", area)
+	}
+
+	_, err := c.ReadWords(MemoryAreaIndexRegisterPV, 0, 1)
+	assert.Equal(t, IncompatibleMemoryAreaError{MemoryAreaIndexRegisterPV}, err)
+}
+
+func TestClient_EMBanks(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	assert.Nil(t, c.WriteWords(MemoryAreaEM0Word, 100, []uint16{1}))
+	assert.Nil(t, c.WriteWords(MemoryAreaEM1Word, 100, []uint16{2}))
+
+	// bank 0 is the current bank of simulator
+	words, err := c.ReadWords(MemoryAreaEMCurrentBankWord, 100, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{1}, words)
+	words, err = c.ReadWords(MemoryAreaEM1Word, 100, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{2}, words)
+
+	assert.Nil(t, c.SetBit(MemoryAreaEMCurrentBankBit, 100, 4))
+	bits, err := c.ReadBits(MemoryAreaEM0Bit, 100, 0, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false, false, false, true}, bits)
+}
+
+func TestClient_DoubleWords(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	assert.Nil(t, c.WriteDoubleWords(MemoryAreaIndexRegisterPV, 14, []uint32{0x12345678, 0x9abcdef0}))
+	values, err := c.ReadDoubleWords(MemoryAreaIndexRegisterPV, 14, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{0x12345678, 0x9abcdef0}, values)
+
+	_, err = c.ReadDoubleWords(MemoryAreaIndexRegisterPV, 15, 2)
+	assert.Equal(t, EndCodeError{EndCodeAddressRangeExceeded}, err)
+	_, err = c.ReadDoubleWords(MemoryAreaDMWord, 0, 1)
+	assert.Equal(t, IncompatibleMemoryAreaError{MemoryAreaDMWord}, err)
+	assert.Equal(t, EmptyWriteRequestError{}, c.WriteDoubleWords(MemoryAreaIndexRegisterPV, 0, nil))
+
+	multiple, err := c.ReadMultiple([]MultipleReadItem{{MemoryArea: MemoryAreaIndexRegisterPV, Address: 15}})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0x9abcdef0), multiple[0].DoubleWord)
+}
+
+func TestClient_readOnlyAreas(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	s.writeMemory(memAddr(MemoryAreaTaskBit, 1), 1, []byte{1})
+	bits, err := c.ReadBits(MemoryAreaTaskBit, 0, 0, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false, true}, bits)
+	bits, err = c.ReadBits(MemoryAreaTaskStatus, 0, 0, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false}, bits)
+
+	for _, area := range []byte{MemoryAreaTaskBit, MemoryAreaTaskStatus, MemoryAreaClockPulsesConditionFlagsBit} {
+		assert.Equal(t, ReadOnlyMemoryAreaError{area}, c.WriteBits(area, 0, 0, []bool{true}))
+		assert.Equal(t, ReadOnlyMemoryAreaError{area}, c.SetBit(area, 0, 0))
+	}
+
+	// simulator rejects the write without client check
+	command := writeCommand(memAddr(MemoryAreaTaskBit, 0), 1, []byte{1})
+	assert.Equal(t, EndCodeWriteNotPossibleReadOnly, s.memoryAreaWrite(command[2:]))
+}
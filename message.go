@@ -0,0 +1,106 @@
+package fins
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// MessageAll message mask of all messages 0 to 7
+	MessageAll byte = 0xff
+
+	// messageRead message read/clear parameter: reads messages in bits 0 to 7
+	messageRead uint16 = 0x0000
+
+	// messageClear message read/clear parameter: clears messages in bits 0 to 7
+	messageClear uint16 = 0x4000
+
+	// messageFALSNameRead message read/clear parameter: reads FAL/FALS number and error message
+	messageFALSNameRead uint16 = 0x8000
+
+	// messageSize each message is 32 bytes ASCII
+	messageSize = 32
+
+	// falsNameSize FAL/FALS number(2) + error message(16)
+	falsNameSize = 18
+)
+
+// ReadMessages Reads messages set by MSG instructions, bit n of mask selects message n
+// returns message number -> message text of messages selected by mask, a message not set is ""
+func (c *Client) ReadMessages(mask byte) (map[byte]string, error) {
+	return c.ReadMessagesContext(context.Background(), mask)
+}
+
+// ReadMessagesContext same as ReadMessages, stops waiting for the response when ctx is done
+func (c *Client) ReadMessagesContext(ctx context.Context, mask byte) (map[byte]string, error) {
+	return wrapRead(c, func() (map[byte]string, error) {
+		r, e := c.sendCommandAndCheckResponse(ctx, messageReadClearCommand(messageRead|uint16(mask)))
+		if e != nil {
+			return nil, e
+		}
+		return decodeMessages(mask, r.data)
+	})
+}
+
+// ClearMessages Clears messages, bit n of mask selects message n
+func (c *Client) ClearMessages(mask byte) error {
+	return c.ClearMessagesContext(context.Background(), mask)
+}
+
+// ClearMessagesContext same as ClearMessages, stops waiting for the response when ctx is done
+func (c *Client) ClearMessagesContext(ctx context.Context, mask byte) error {
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, messageReadClearCommand(messageClear|uint16(mask))))
+	})
+}
+
+// ReadFALSNames Reads FAL/FALS number and error message of the current FAL/FALS error
+// returns FAL/FALS number -> error message, empty if there is no FAL/FALS error
+func (c *Client) ReadFALSNames() (map[uint16]string, error) {
+	return c.ReadFALSNamesContext(context.Background())
+}
+
+// ReadFALSNamesContext same as ReadFALSNames, stops waiting for the response when ctx is done
+func (c *Client) ReadFALSNamesContext(ctx context.Context) (map[uint16]string, error) {
+	return wrapRead(c, func() (map[uint16]string, error) {
+		r, e := c.sendCommandAndCheckResponse(ctx, messageReadClearCommand(messageFALSNameRead))
+		if e != nil {
+			return nil, e
+		}
+		if len(r.data) != falsNameSize {
+			return nil, ResponseLengthError{want: falsNameSize, got: len(r.data)}
+		}
+		names := map[uint16]string{}
+		if n := binary.BigEndian.Uint16(r.data[0:2]); n != 0 {
+			names[n] = decodeASCII(r.data[2:18])
+		}
+		return names, nil
+	})
+}
+
+// decodeMessages data is the message number parameter followed by 32 bytes of each message in mask
+func decodeMessages(mask byte, data []byte) (map[byte]string, error) {
+	want := 2
+	for n := 0; n < 8; n++ {
+		if mask&(1<<n) != 0 {
+			want += messageSize
+		}
+	}
+	if len(data) != want {
+		return nil, ResponseLengthError{want: want, got: len(data)}
+	}
+	if got := binary.BigEndian.Uint16(data[0:2]); got != uint16(mask) {
+		return nil, fmt.Errorf("failed to decode messages: want message mask %02X, got: %04X", mask, got)
+	}
+	messages := map[byte]string{}
+	data = data[2:]
+	for n := byte(0); n < 8; n++ {
+		if mask&(1<<n) == 0 {
+			continue
+		}
+		messages[n] = decodeASCII(data[:messageSize])
+		data = data[messageSize:]
+	}
+	return messages, nil
+}
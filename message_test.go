@@ -0,0 +1,56 @@
+package fins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ReadMessages(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	s.SetMessage(0, "CONVEYOR 1 JAM")
+	s.SetMessage(3, "LOW AIR PRESSURE")
+	s.SetMessage(7, "0123456789ABCDEF0123456789ABCDEF")
+
+	messages, err := c.ReadMessages(MessageAll)
+	assert.Nil(t, err)
+	assert.Equal(t, map[byte]string{0: "CONVEYOR 1 JAM", 1: "", 2: "", 3: "LOW AIR PRESSURE",
+		4: "", 5: "", 6: "", 7: "0123456789ABCDEF0123456789ABCDEF"}, messages)
+
+	messages, err = c.ReadMessages(0x09)
+	assert.Nil(t, err)
+	assert.Equal(t, map[byte]string{0: "CONVEYOR 1 JAM", 3: "LOW AIR PRESSURE"}, messages)
+
+	status, _ := c.ReadCPUStatus()
+	assert.Equal(t, uint16(0x89), status.MessageFlags)
+
+	assert.Nil(t, c.ClearMessages(0x81))
+	messages, err = c.ReadMessages(0x89)
+	assert.Nil(t, err)
+	assert.Equal(t, map[byte]string{0: "", 3: "LOW AIR PRESSURE", 7: ""}, messages)
+}
+
+func TestClient_ReadFALSNames(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	names, err := c.ReadFALSNames()
+	assert.Nil(t, err)
+	assert.Empty(t, names)
+
+	s.LogError(0xC10A, 0)
+	s.errorMessage = "E-STOP"
+	names, err = c.ReadFALSNames()
+	assert.Nil(t, err)
+	assert.Equal(t, map[uint16]string{10: "E-STOP"}, names)
+}
+
+func Test_decodeMessages(t *testing.T) {
+	_, err := decodeMessages(0x03, make([]byte, 34))
+	assert.Equal(t, ResponseLengthError{want: 66, got: 34}, err)
+
+	_, err = decodeMessages(0x01, make([]byte, 34))
+	assert.NotNil(t, err, "message mask mismatch")
+}
@@ -0,0 +1,256 @@
+package fins
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// WordOrder order of bytes in 32-bit and 64-bit values stored in PLC words,
+// A is the most significant byte and each word is big endian in FINS frames
+type WordOrder byte
+
+const (
+	// WordOrderCDAB low word first, Omron stores DINT, UDINT, REAL and LREAL in this order
+	WordOrderCDAB WordOrder = iota
+
+	// WordOrderABCD high word first
+	WordOrderABCD
+
+	// WordOrderBADC high word first, bytes of each word swapped
+	WordOrderBADC
+
+	// WordOrderDCBA low word first, bytes of each word swapped (little endian)
+	WordOrderDCBA
+)
+
+// String name of the word order
+func (o WordOrder) String() string {
+	switch o {
+	case WordOrderCDAB:
+		return "CDAB"
+	case WordOrderABCD:
+		return "ABCD"
+	case WordOrderBADC:
+		return "BADC"
+	case WordOrderDCBA:
+		return "DCBA"
+	default:
+		return fmt.Sprintf("WordOrder(%d)", byte(o))
+	}
+}
+
+// arrange converts big endian value b to the word order in place, or back since each order is its own inverse
+func (o WordOrder) arrange(b []byte) {
+	if o == WordOrderCDAB || o == WordOrderDCBA {
+		for i, j := 0, len(b)-2; i < j; i, j = i+2, j-2 {
+			b[i], b[i+1], b[j], b[j+1] = b[j], b[j+1], b[i], b[i+1]
+		}
+	}
+	if o == WordOrderBADC || o == WordOrderDCBA {
+		for i := 0; i+1 < len(b); i += 2 {
+			b[i], b[i+1] = b[i+1], b[i]
+		}
+	}
+}
+
+// SetWordOrder
+// Set word order of 32-bit and 64-bit values, it also decides the byte order inside their words,
+// the byte order set by SetByteOrder applies to words and 16-bit values
+// Default value: WordOrderCDAB
+func (c *Client) SetWordOrder(o WordOrder) {
+	c.wordOrder.Store(int32(o))
+}
+
+// WordOrder returns word order of 32-bit and 64-bit values
+func (c *Client) WordOrder() WordOrder {
+	return WordOrder(c.wordOrder.Load())
+}
+
+// ReadInt16s Reads signed 16-bit values (INT), one per word
+func (c *Client) ReadInt16s(memoryArea byte, address uint16, readCount uint16) ([]int16, error) {
+	return c.ReadInt16sContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadInt16sContext same as ReadInt16s, stops waiting for the response when ctx is done
+func (c *Client) ReadInt16sContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]int16, error) {
+	words, err := c.ReadWordsContext(ctx, memoryArea, address, readCount)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]int16, len(words))
+	for i, w := range words {
+		values[i] = int16(w)
+	}
+	return values, nil
+}
+
+// WriteInt16s Writes signed 16-bit values (INT), one per word
+func (c *Client) WriteInt16s(memoryArea byte, address uint16, data []int16) error {
+	return c.WriteInt16sContext(context.Background(), memoryArea, address, data)
+}
+
+// WriteInt16sContext same as WriteInt16s, stops waiting for the response when ctx is done
+func (c *Client) WriteInt16sContext(ctx context.Context, memoryArea byte, address uint16, data []int16) error {
+	words := make([]uint16, len(data))
+	for i, v := range data {
+		words[i] = uint16(v)
+	}
+	return c.WriteWordsContext(ctx, memoryArea, address, words)
+}
+
+// ReadInt32s Reads signed 32-bit values (DINT), two words per value
+// Example:
+//
+//	ReadInt32s(D, 100, 2) reads D100-D103
+func (c *Client) ReadInt32s(memoryArea byte, address uint16, readCount uint16) ([]int32, error) {
+	return c.ReadInt32sContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadInt32sContext same as ReadInt32s, stops waiting for the response when ctx is done
+func (c *Client) ReadInt32sContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]int32, error) {
+	return readValues(c, ctx, memoryArea, address, readCount, 4, func(b []byte) int32 {
+		return int32(binary.BigEndian.Uint32(b))
+	})
+}
+
+// WriteInt32s Writes signed 32-bit values (DINT), two words per value
+func (c *Client) WriteInt32s(memoryArea byte, address uint16, data []int32) error {
+	return c.WriteInt32sContext(context.Background(), memoryArea, address, data)
+}
+
+// WriteInt32sContext same as WriteInt32s, stops waiting for the response when ctx is done
+func (c *Client) WriteInt32sContext(ctx context.Context, memoryArea byte, address uint16, data []int32) error {
+	return writeValues(c, ctx, memoryArea, address, data, 4, func(b []byte, v int32) {
+		binary.BigEndian.PutUint32(b, uint32(v))
+	})
+}
+
+// ReadUint32s Reads unsigned 32-bit values (UDINT), two words per value
+func (c *Client) ReadUint32s(memoryArea byte, address uint16, readCount uint16) ([]uint32, error) {
+	return c.ReadUint32sContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadUint32sContext same as ReadUint32s, stops waiting for the response when ctx is done
+func (c *Client) ReadUint32sContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]uint32, error) {
+	return readValues(c, ctx, memoryArea, address, readCount, 4, binary.BigEndian.Uint32)
+}
+
+// WriteUint32s Writes unsigned 32-bit values (UDINT), two words per value
+func (c *Client) WriteUint32s(memoryArea byte, address uint16, data []uint32) error {
+	return c.WriteUint32sContext(context.Background(), memoryArea, address, data)
+}
+
+// WriteUint32sContext same as WriteUint32s, stops waiting for the response when ctx is done
+func (c *Client) WriteUint32sContext(ctx context.Context, memoryArea byte, address uint16, data []uint32) error {
+	return writeValues(c, ctx, memoryArea, address, data, 4, binary.BigEndian.PutUint32)
+}
+
+// ReadInt64s Reads signed 64-bit values (LINT), four words per value
+func (c *Client) ReadInt64s(memoryArea byte, address uint16, readCount uint16) ([]int64, error) {
+	return c.ReadInt64sContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadInt64sContext same as ReadInt64s, stops waiting for the response when ctx is done
+func (c *Client) ReadInt64sContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]int64, error) {
+	return readValues(c, ctx, memoryArea, address, readCount, 8, func(b []byte) int64 {
+		return int64(binary.BigEndian.Uint64(b))
+	})
+}
+
+// WriteInt64s Writes signed 64-bit values (LINT), four words per value
+func (c *Client) WriteInt64s(memoryArea byte, address uint16, data []int64) error {
+	return c.WriteInt64sContext(context.Background(), memoryArea, address, data)
+}
+
+// WriteInt64sContext same as WriteInt64s, stops waiting for the response when ctx is done
+func (c *Client) WriteInt64sContext(ctx context.Context, memoryArea byte, address uint16, data []int64) error {
+	return writeValues(c, ctx, memoryArea, address, data, 8, func(b []byte, v int64) {
+		binary.BigEndian.PutUint64(b, uint64(v))
+	})
+}
+
+// ReadFloat32s Reads 32-bit floating point values (REAL), two words per value
+func (c *Client) ReadFloat32s(memoryArea byte, address uint16, readCount uint16) ([]float32, error) {
+	return c.ReadFloat32sContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadFloat32sContext same as ReadFloat32s, stops waiting for the response when ctx is done
+func (c *Client) ReadFloat32sContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]float32, error) {
+	return readValues(c, ctx, memoryArea, address, readCount, 4, func(b []byte) float32 {
+		return math.Float32frombits(binary.BigEndian.Uint32(b))
+	})
+}
+
+// WriteFloat32s Writes 32-bit floating point values (REAL), two words per value
+func (c *Client) WriteFloat32s(memoryArea byte, address uint16, data []float32) error {
+	return c.WriteFloat32sContext(context.Background(), memoryArea, address, data)
+}
+
+// WriteFloat32sContext same as WriteFloat32s, stops waiting for the response when ctx is done
+func (c *Client) WriteFloat32sContext(ctx context.Context, memoryArea byte, address uint16, data []float32) error {
+	return writeValues(c, ctx, memoryArea, address, data, 4, func(b []byte, v float32) {
+		binary.BigEndian.PutUint32(b, math.Float32bits(v))
+	})
+}
+
+// ReadFloat64s Reads 64-bit floating point values (LREAL), four words per value
+func (c *Client) ReadFloat64s(memoryArea byte, address uint16, readCount uint16) ([]float64, error) {
+	return c.ReadFloat64sContext(context.Background(), memoryArea, address, readCount)
+}
+
+// ReadFloat64sContext same as ReadFloat64s, stops waiting for the response when ctx is done
+func (c *Client) ReadFloat64sContext(ctx context.Context, memoryArea byte, address uint16, readCount uint16) ([]float64, error) {
+	return readValues(c, ctx, memoryArea, address, readCount, 8, func(b []byte) float64 {
+		return math.Float64frombits(binary.BigEndian.Uint64(b))
+	})
+}
+
+// WriteFloat64s Writes 64-bit floating point values (LREAL), four words per value
+func (c *Client) WriteFloat64s(memoryArea byte, address uint16, data []float64) error {
+	return c.WriteFloat64sContext(context.Background(), memoryArea, address, data)
+}
+
+// WriteFloat64sContext same as WriteFloat64s, stops waiting for the response when ctx is done
+func (c *Client) WriteFloat64sContext(ctx context.Context, memoryArea byte, address uint16, data []float64) error {
+	return writeValues(c, ctx, memoryArea, address, data, 8, func(b []byte, v float64) {
+		binary.BigEndian.PutUint64(b, math.Float64bits(v))
+	})
+}
+
+// readValues reads readCount values of size bytes and decodes them from big endian after arranging the word order
+func readValues[T any](c *Client, ctx context.Context, memoryArea byte, address uint16, readCount uint16,
+	size int, decode func([]byte) T) ([]T, error) {
+	words := int(readCount) * size / 2
+	if words > math.MaxUint16 {
+		return nil, fmt.Errorf("failed to read %d values of %d bytes: more than %d words", readCount, size, math.MaxUint16)
+	}
+	b, err := c.ReadBytesContext(ctx, memoryArea, address, uint16(words))
+	if err != nil {
+		return nil, err
+	}
+	order := c.WordOrder()
+	values := make([]T, readCount)
+	for i := range values {
+		v := b[i*size : i*size+size]
+		order.arrange(v)
+		values[i] = decode(v)
+	}
+	return values, nil
+}
+
+// writeValues encodes data to big endian, arranges the word order and writes them
+func writeValues[T any](c *Client, ctx context.Context, memoryArea byte, address uint16, data []T,
+	size int, encode func([]byte, T)) error {
+	if len(data) == 0 {
+		return EmptyWriteRequestError{}
+	}
+	order := c.WordOrder()
+	b := make([]byte, len(data)*size)
+	for i, v := range data {
+		encode(b[i*size:i*size+size], v)
+		order.arrange(b[i*size : i*size+size])
+	}
+	return c.WriteBytesContext(ctx, memoryArea, address, b)
+}
@@ -0,0 +1,91 @@
+package fins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordOrder_arrange(t *testing.T) {
+	for _, tt := range []struct {
+		order WordOrder
+		want  []byte
+	}{
+		{WordOrderABCD, []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		{WordOrderCDAB, []byte{7, 8, 5, 6, 3, 4, 1, 2}},
+		{WordOrderBADC, []byte{2, 1, 4, 3, 6, 5, 8, 7}},
+		{WordOrderDCBA, []byte{8, 7, 6, 5, 4, 3, 2, 1}},
+	} {
+		b := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+		tt.order.arrange(b)
+		assert.Equal(t, tt.want, b, tt.order.String())
+		tt.order.arrange(b)
+		assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, b, tt.order.String())
+	}
+}
+
+func TestClient_Float32s(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	// 1.5 is 0x3FC00000
+	for _, tt := range []struct {
+		order WordOrder
+		words []uint16
+	}{
+		{WordOrderCDAB, []uint16{0x0000, 0x3fc0}},
+		{WordOrderABCD, []uint16{0x3fc0, 0x0000}},
+		{WordOrderBADC, []uint16{0xc03f, 0x0000}},
+		{WordOrderDCBA, []uint16{0x0000, 0xc03f}},
+	} {
+		c.SetWordOrder(tt.order)
+		assert.Equal(t, tt.order, c.WordOrder())
+		assert.Nil(t, c.WriteFloat32s(MemoryAreaDMWord, 100, []float32{1.5, -2}))
+		words, err := c.ReadWords(MemoryAreaDMWord, 100, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.words, words, tt.order.String())
+		values, err := c.ReadFloat32s(MemoryAreaDMWord, 100, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []float32{1.5, -2}, values, tt.order.String())
+	}
+}
+
+func TestClient_numericValues(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	assert.Nil(t, c.WriteFloat64s(MemoryAreaDMWord, 10, []float64{15.6, -0.25}))
+	f64s, err := c.ReadFloat64s(MemoryAreaDMWord, 10, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{15.6, -0.25}, f64s)
+
+	assert.Nil(t, c.WriteInt32s(MemoryAreaDMWord, 20, []int32{-2, 0x12345678}))
+	words, err := c.ReadWords(MemoryAreaDMWord, 20, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0xfffe, 0xffff, 0x5678, 0x1234}, words)
+	i32s, err := c.ReadInt32s(MemoryAreaDMWord, 20, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{-2, 0x12345678}, i32s)
+
+	u32s, err := c.ReadUint32s(MemoryAreaDMWord, 20, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{0xfffffffe, 0x12345678}, u32s)
+	assert.Nil(t, c.WriteUint32s(MemoryAreaDMWord, 20, []uint32{7}))
+	u32s, err = c.ReadUint32s(MemoryAreaDMWord, 20, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{7}, u32s)
+
+	assert.Nil(t, c.WriteInt64s(MemoryAreaDMWord, 30, []int64{-3}))
+	i64s, err := c.ReadInt64s(MemoryAreaDMWord, 30, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{-3}, i64s)
+
+	assert.Nil(t, c.WriteInt16s(MemoryAreaDMWord, 40, []int16{-1, 2}))
+	i16s, err := c.ReadInt16s(MemoryAreaDMWord, 40, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int16{-1, 2}, i16s)
+
+	assert.Equal(t, EmptyWriteRequestError{}, c.WriteFloat32s(MemoryAreaDMWord, 0, nil))
+	_, err = c.ReadFloat64s(MemoryAreaDMWord, 0, 20000)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,49 @@
+package fins
+
+import (
+	"context"
+)
+
+const (
+	// OperatingModeProgram Operating mode: PROGRAM, program is stopped
+	OperatingModeProgram byte = 0x00
+
+	// OperatingModeMonitor Operating mode: MONITOR, program is running and online changes are allowed
+	OperatingModeMonitor byte = 0x02
+
+	// OperatingModeRun Operating mode: RUN, program is running
+	OperatingModeRun byte = 0x04
+)
+
+// ProgramNumberAll program number for CS/CJ/CP CPU units, they have only one program
+const ProgramNumberAll uint16 = 0xffff
+
+// Run Changes the PLC operating mode to MONITOR or RUN
+// mode is OperatingModeMonitor or OperatingModeRun, programNumber is ProgramNumberAll for CS/CJ/CP CPU units
+// returns OperatingModeError if the PLC can not change mode now
+func (c *Client) Run(mode byte, programNumber uint16) error {
+	return c.RunContext(context.Background(), mode, programNumber)
+}
+
+// RunContext same as Run, stops waiting for the response when ctx is done
+func (c *Client) RunContext(ctx context.Context, mode byte, programNumber uint16) error {
+	if mode != OperatingModeMonitor && mode != OperatingModeRun {
+		return InvalidOperatingModeError{mode}
+	}
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, runCommand(programNumber, mode)))
+	})
+}
+
+// Stop Changes the PLC operating mode to PROGRAM
+// returns OperatingModeError if the PLC can not change mode now
+func (c *Client) Stop() error {
+	return c.StopContext(context.Background())
+}
+
+// StopContext same as Stop, stops waiting for the response when ctx is done
+func (c *Client) StopContext(ctx context.Context) error {
+	return c.wrapOperate(func() error {
+		return c.checkResponse(c.sendCommand(ctx, stopCommand(ProgramNumberAll)))
+	})
+}
@@ -0,0 +1,36 @@
+package fins
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_RunAndStop(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	err := c.Run(OperatingModeMonitor, ProgramNumberAll)
+	assert.Nil(t, err)
+	assert.Equal(t, OperatingModeMonitor, s.mode)
+	assert.Nil(t, c.WriteWords(MemoryAreaDMWord, 0, []uint16{1}), "MONITOR mode allows writes")
+
+	err = c.Run(OperatingModeRun, ProgramNumberAll)
+	assert.Nil(t, err)
+	assert.Nil(t, c.WriteWords(MemoryAreaDMWord, 0, []uint16{1}), "RUN mode allows I/O memory writes")
+	assert.Nil(t, c.FillWords(MemoryAreaDMWord, 0, 2, 3), "RUN mode allows I/O memory fill")
+	err = c.WriteParameterArea(ParameterAreaPLCSetup, 0, []uint16{1})
+	var modeErr OperatingModeError
+	assert.True(t, errors.As(err, &modeErr))
+	assert.Equal(t, EndCodeNotExecutableInCurrentModeWrongPLCModeInRun, modeErr.EndCode())
+	var endCodeErr EndCodeError
+	assert.True(t, errors.As(err, &endCodeErr), "OperatingModeError should unwrap to EndCodeError")
+
+	err = c.Stop()
+	assert.Nil(t, err)
+	assert.Equal(t, OperatingModeProgram, s.mode)
+	assert.Nil(t, c.WriteWords(MemoryAreaDMWord, 0, []uint16{1}))
+
+	assert.Equal(t, InvalidOperatingModeError{OperatingModeProgram}, c.Run(OperatingModeProgram, ProgramNumberAll))
+}
@@ -0,0 +1,144 @@
+package fins
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+)
+
+// ParameterArea parameter area code
+type ParameterArea uint16
+
+const (
+	// ParameterAreaCPUBusUnitSetup parameter area: CPU bus unit setup area
+	ParameterAreaCPUBusUnitSetup ParameterArea = 0x8002
+
+	// ParameterAreaPLCSetup parameter area: PLC setup area
+	ParameterAreaPLCSetup ParameterArea = 0x8010
+
+	// ParameterAreaIOTable parameter area: registered I/O table area
+	ParameterAreaIOTable ParameterArea = 0x8012
+
+	// ParameterAreaRoutingTable parameter area: routing table area
+	ParameterAreaRoutingTable ParameterArea = 0x8013
+)
+
+// parameterAreaWords words of each parameter area, same as CS/CJ CPU units
+var parameterAreaWords = map[ParameterArea]uint16{
+	ParameterAreaCPUBusUnitSetup: 0x1440,
+	ParameterAreaPLCSetup:        0x0200,
+	ParameterAreaIOTable:         0x0500,
+	ParameterAreaRoutingTable:    0x0200,
+}
+
+const (
+	// parameterAreaChunkWords max words read or written by one command
+	parameterAreaChunkWords = 128
+
+	// parameterAreaLastFlag bit 15 of number of words, set in the response including the last word of the area
+	// and in the command writing the last block
+	parameterAreaLastFlag uint16 = 0x8000
+)
+
+// Words number of words in the parameter area
+func (a ParameterArea) Words() uint16 {
+	return parameterAreaWords[a]
+}
+
+// ReadParameterArea Reads count words of the parameter area from address, in chunks of 128 words
+func (c *Client) ReadParameterArea(area ParameterArea, address, count uint16) ([]uint16, error) {
+	return c.ReadParameterAreaContext(context.Background(), area, address, count)
+}
+
+// ReadParameterAreaContext same as ReadParameterArea, stops waiting for the response when ctx is done
+func (c *Client) ReadParameterAreaContext(ctx context.Context, area ParameterArea, address, count uint16) ([]uint16, error) {
+	if err := checkParameterArea(area, address, count); err != nil {
+		return nil, err
+	}
+	return wrapRead(c, func() ([]uint16, error) {
+		words := make([]uint16, 0, count)
+		for len(words) < int(count) {
+			start, n := address+uint16(len(words)), count-uint16(len(words))
+			if n > parameterAreaChunkWords {
+				n = parameterAreaChunkWords
+			}
+			r, err := c.sendCommandAndCheckResponse(ctx, parameterAreaCommand(CommandCodeParameterAreaRead, area, start, n, nil))
+			if err != nil {
+				return nil, err
+			}
+			if len(r.data) != 6+int(n)*2 {
+				return nil, ResponseLengthError{want: 6 + int(n)*2, got: len(r.data)}
+			}
+			if ParameterArea(binary.BigEndian.Uint16(r.data[0:2])) != area ||
+				binary.BigEndian.Uint16(r.data[2:4]) != start ||
+				binary.BigEndian.Uint16(r.data[4:6])&^parameterAreaLastFlag != n {
+				return nil, fmt.Errorf("failed to read parameter area: want %d words of 0x%04X from %d, got: % X",
+					n, uint16(area), start, r.data[0:6])
+			}
+			words = append(words, c.bytesToUint16s(r.data[6:])...)
+		}
+		return words, nil
+	})
+}
+
+// WriteParameterArea Writes words to the parameter area from address, in chunks of 128 words
+// the PLC must be in PROGRAM mode
+func (c *Client) WriteParameterArea(area ParameterArea, address uint16, data []uint16) error {
+	return c.WriteParameterAreaContext(context.Background(), area, address, data)
+}
+
+// WriteParameterAreaContext same as WriteParameterArea, stops waiting for the response when ctx is done
+func (c *Client) WriteParameterAreaContext(ctx context.Context, area ParameterArea, address uint16, data []uint16) error {
+	if len(data) == 0 {
+		return EmptyWriteRequestError{}
+	}
+	if err := checkParameterArea(area, address, uint16(len(data))); err != nil {
+		return err
+	}
+	return c.wrapOperate(func() error {
+		for start := 0; start < len(data); start += parameterAreaChunkWords {
+			end := start + parameterAreaChunkWords
+			if end > len(data) {
+				end = len(data)
+			}
+			n := uint16(end - start)
+			if end == len(data) {
+				n |= parameterAreaLastFlag
+			}
+			command := parameterAreaCommand(CommandCodeParameterAreaWrite, area, address+uint16(start), n, c.uint16sToBytes(data[start:end]))
+			if err := c.checkResponse(c.sendCommand(ctx, command)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ClearParameterArea Clears all words of the parameter area to 0
+// the PLC must be in PROGRAM mode
+func (c *Client) ClearParameterArea(area ParameterArea) error {
+	return c.ClearParameterAreaContext(context.Background(), area)
+}
+
+// ClearParameterAreaContext same as ClearParameterArea, stops waiting for the response when ctx is done
+func (c *Client) ClearParameterAreaContext(ctx context.Context, area ParameterArea) error {
+	if err := checkParameterArea(area, 0, 0); err != nil {
+		return err
+	}
+	return c.wrapOperate(func() error {
+		// the clear data is always 0x0000
+		command := parameterAreaCommand(CommandCodeParameterAreaClear, area, 0, area.Words(), []byte{0, 0})
+		return c.checkResponse(c.sendCommand(ctx, command))
+	})
+}
+
+func checkParameterArea(area ParameterArea, address, count uint16) error {
+	words, ok := parameterAreaWords[area]
+	if !ok {
+		return IncompatibleParameterAreaError{area}
+	}
+	if int(address)+int(count) > int(words) {
+		return ParameterAreaRangeError{area, address, count}
+	}
+	return nil
+}
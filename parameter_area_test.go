@@ -0,0 +1,61 @@
+package fins
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ParameterArea(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	data := make([]uint16, 300)
+	for i := range data {
+		data[i] = uint16(i + 1)
+	}
+	assert.Nil(t, c.WriteParameterArea(ParameterAreaPLCSetup, 100, data))
+	words, err := c.ReadParameterArea(ParameterAreaPLCSetup, 100, 300)
+	assert.Nil(t, err)
+	assert.Equal(t, data, words)
+
+	words, err = c.ReadParameterArea(ParameterAreaPLCSetup, 0, ParameterAreaPLCSetup.Words())
+	assert.Nil(t, err)
+	assert.Len(t, words, int(ParameterAreaPLCSetup.Words()))
+	assert.Equal(t, uint16(1), words[100])
+
+	assert.Nil(t, c.ClearParameterArea(ParameterAreaPLCSetup))
+	words, err = c.ReadParameterArea(ParameterAreaPLCSetup, 100, 300)
+	assert.Nil(t, err)
+	assert.Equal(t, make([]uint16, 300), words)
+
+	assert.Nil(t, c.Run(OperatingModeMonitor, ProgramNumberAll))
+	var modeErr OperatingModeError
+	assert.True(t, errors.As(c.WriteParameterArea(ParameterAreaIOTable, 0, []uint16{1}), &modeErr))
+	assert.True(t, errors.As(c.ClearParameterArea(ParameterAreaRoutingTable), &modeErr))
+	_, err = c.ReadParameterArea(ParameterAreaCPUBusUnitSetup, 0, 10)
+	assert.Nil(t, err, "parameter area can be read in any mode")
+}
+
+func TestClient_ParameterArea_error(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	_, err := c.ReadParameterArea(0x8001, 0, 1)
+	assert.Equal(t, IncompatibleParameterAreaError{0x8001}, err)
+	_, err = c.ReadParameterArea(ParameterAreaRoutingTable, 0x01ff, 2)
+	assert.Equal(t, ParameterAreaRangeError{ParameterAreaRoutingTable, 0x01ff, 2}, err)
+	assert.Equal(t, EmptyWriteRequestError{}, c.WriteParameterArea(ParameterAreaPLCSetup, 0, nil))
+}
+
+func TestSimulator_cancelForcedKeepsParameterArea(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	assert.Nil(t, c.WriteParameterArea(ParameterAreaIOTable, 10, []uint16{1, 2, 3}))
+	assert.Nil(t, c.CancelAllForced())
+	words, err := c.ReadParameterArea(ParameterAreaIOTable, 10, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{1, 2, 3}, words)
+}
@@ -0,0 +1,175 @@
+package fins
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+const (
+	// programChunkSize max program bytes read or written by one command
+	programChunkSize = 996
+
+	// programLastFlag bit 15 of number of bytes, set in the response or command including the last block
+	programLastFlag uint16 = 0x8000
+
+	// programClearCode program area clear code: clears all the program
+	programClearCode byte = 0x00
+
+	// programArchiveVersion format version of program archive
+	programArchiveVersion byte = 1
+
+	// programArchiveHeaderSize magic(7) + version(1) + model(20) + CPU version(20) + backup time(8) + size(4) + CRC-32(4)
+	programArchiveHeaderSize = 64
+)
+
+var programArchiveMagic = [7]byte{'F', 'I', 'N', 'S', 'P', 'R', 'G'}
+
+// ProgramBackup Reads the user program and writes it to w as an archive
+// the archive records model and version of the CPU unit, backup time, program size and CRC-32 of the program,
+// so ProgramRestore can check it before writing
+func (c *Client) ProgramBackup(w io.Writer) error {
+	return c.ProgramBackupContext(context.Background(), w)
+}
+
+// ProgramBackupContext same as ProgramBackup, stops waiting for the response when ctx is done
+func (c *Client) ProgramBackupContext(ctx context.Context, w io.Writer) error {
+	unit, err := c.ReadCPUUnitDataContext(ctx)
+	if err != nil {
+		return err
+	}
+	program, err := wrapRead(c, func() ([]byte, error) {
+		return c.readProgram(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	header := encodeProgramArchiveHeader(unit, time.Now(), program)
+	if _, err = w.Write(header); err != nil {
+		return err
+	}
+	_, err = w.Write(program)
+	return err
+}
+
+// ProgramRestore Clears the user program and writes the program in the archive read from r
+// the PLC must be in PROGRAM mode, returns NotInProgramModeError otherwise.
+// returns ProgramArchiveError if the archive is broken or it is backed up from another CPU unit model
+func (c *Client) ProgramRestore(r io.Reader) error {
+	return c.ProgramRestoreContext(context.Background(), r)
+}
+
+// ProgramRestoreContext same as ProgramRestore, stops waiting for the response when ctx is done
+func (c *Client) ProgramRestoreContext(ctx context.Context, r io.Reader) error {
+	model, program, err := decodeProgramArchive(r)
+	if err != nil {
+		return err
+	}
+	unit, err := c.ReadCPUUnitDataContext(ctx)
+	if err != nil {
+		return err
+	}
+	if unit.Model != model {
+		return ProgramArchiveError{fmt.Sprintf("archive is backed up from %s, the PLC is %s", model, unit.Model)}
+	}
+	status, err := c.ReadCPUStatusContext(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Mode != OperatingModeProgram {
+		return NotInProgramModeError{status.Mode}
+	}
+	return c.wrapOperate(func() error {
+		if err := c.checkResponse(c.sendCommand(ctx, programAreaClearCommand(ProgramNumberAll))); err != nil {
+			return err
+		}
+		return c.writeProgram(ctx, program)
+	})
+}
+
+// readProgram reads the program area from offset 0 until the last block
+func (c *Client) readProgram(ctx context.Context) ([]byte, error) {
+	var program []byte
+	for {
+		offset := uint32(len(program))
+		r, err := c.sendCommandAndCheckResponse(ctx, programAreaReadCommand(ProgramNumberAll, offset, programChunkSize))
+		if err != nil {
+			return nil, err
+		}
+		if len(r.data) < 8 {
+			return nil, ResponseLengthError{want: 8, got: len(r.data)}
+		}
+		if got := binary.BigEndian.Uint32(r.data[2:6]); got != offset {
+			return nil, fmt.Errorf("failed to read program: want offset %d, got: %d", offset, got)
+		}
+		length := binary.BigEndian.Uint16(r.data[6:8])
+		last := length&programLastFlag != 0
+		length &^= programLastFlag
+		if length > programChunkSize || len(r.data) != 8+int(length) {
+			return nil, ResponseLengthError{want: 8 + int(length), got: len(r.data)}
+		}
+		program = append(program, r.data[8:]...)
+		if last {
+			return program, nil
+		}
+		if length == 0 {
+			return nil, fmt.Errorf("failed to read program: no data and no last block flag at offset %d", offset)
+		}
+	}
+}
+
+// writeProgram writes program in blocks, the last block flag is set in the last one
+func (c *Client) writeProgram(ctx context.Context, program []byte) error {
+	for offset := 0; ; {
+		end := offset + programChunkSize
+		if end > len(program) {
+			end = len(program)
+		}
+		command := programAreaWriteCommand(ProgramNumberAll, uint32(offset), program[offset:end], end == len(program))
+		if err := c.checkResponse(c.sendCommand(ctx, command)); err != nil {
+			return err
+		}
+		if end == len(program) {
+			return nil
+		}
+		offset = end
+	}
+}
+
+func encodeProgramArchiveHeader(unit *CPUUnitData, t time.Time, program []byte) []byte {
+	header := make([]byte, programArchiveHeaderSize)
+	copy(header[0:7], programArchiveMagic[:])
+	header[7] = programArchiveVersion
+	copy(header[8:28], unit.Model)
+	copy(header[28:48], unit.Version)
+	binary.BigEndian.PutUint64(header[48:56], uint64(t.Unix()))
+	binary.BigEndian.PutUint32(header[56:60], uint32(len(program)))
+	binary.BigEndian.PutUint32(header[60:64], crc32.ChecksumIEEE(program))
+	return header
+}
+
+// decodeProgramArchive returns CPU unit model and the program in the archive
+func decodeProgramArchive(r io.Reader) (string, []byte, error) {
+	header := make([]byte, programArchiveHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return "", nil, ProgramArchiveError{fmt.Sprintf("failed to read header: %s", err)}
+	}
+	if !bytes.Equal(header[0:7], programArchiveMagic[:]) {
+		return "", nil, ProgramArchiveError{"not a program archive"}
+	}
+	if header[7] != programArchiveVersion {
+		return "", nil, ProgramArchiveError{fmt.Sprintf("unsupported version %d", header[7])}
+	}
+	program := make([]byte, binary.BigEndian.Uint32(header[56:60]))
+	if _, err := io.ReadFull(r, program); err != nil {
+		return "", nil, ProgramArchiveError{fmt.Sprintf("failed to read program of %d bytes: %s", len(program), err)}
+	}
+	if crc32.ChecksumIEEE(program) != binary.BigEndian.Uint32(header[60:64]) {
+		return "", nil, ProgramArchiveError{"CRC-32 mismatch"}
+	}
+	return decodeASCII(header[8:28]), program, nil
+}
@@ -0,0 +1,70 @@
+package fins
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ProgramBackupRestore(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	program := make([]byte, programChunkSize*3+10)
+	for i := range program {
+		program[i] = byte(i * 7)
+	}
+	s.program = append([]byte{}, program...)
+
+	var archive bytes.Buffer
+	assert.Nil(t, c.ProgramBackup(&archive))
+	assert.Equal(t, programArchiveHeaderSize+len(program), archive.Len())
+
+	s.program = []byte{1, 2, 3}
+	assert.Nil(t, c.ProgramRestore(bytes.NewReader(archive.Bytes())))
+	assert.Equal(t, program, s.program)
+
+	assert.Nil(t, c.Run(OperatingModeRun, ProgramNumberAll))
+	var modeErr NotInProgramModeError
+	assert.True(t, errors.As(c.ProgramRestore(bytes.NewReader(archive.Bytes())), &modeErr))
+	assert.Nil(t, c.Stop())
+
+	s.SetCPUUnitData(CPUUnitData{Model: "CS1G-CPU42H"})
+	assert.IsType(t, ProgramArchiveError{}, c.ProgramRestore(bytes.NewReader(archive.Bytes())), "another model")
+}
+
+func TestClient_ProgramBackup_empty(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	var archive bytes.Buffer
+	assert.Nil(t, c.ProgramBackup(&archive))
+	model, program, err := decodeProgramArchive(&archive)
+	assert.Nil(t, err)
+	assert.Equal(t, "CJ2M-CPU33", model)
+	assert.Empty(t, program)
+}
+
+func Test_decodeProgramArchive(t *testing.T) {
+	program := []byte("program")
+	archive := append(encodeProgramArchiveHeader(&CPUUnitData{Model: "CJ2M-CPU33"}, time.Time{}, program), program...)
+
+	_, _, err := decodeProgramArchive(bytes.NewReader(archive[:10]))
+	assert.IsType(t, ProgramArchiveError{}, err)
+
+	_, _, err = decodeProgramArchive(bytes.NewReader(archive[:len(archive)-1]))
+	assert.IsType(t, ProgramArchiveError{}, err)
+
+	broken := append([]byte{}, archive...)
+	broken[len(broken)-1]++
+	_, _, err = decodeProgramArchive(bytes.NewReader(broken))
+	assert.Equal(t, ProgramArchiveError{"CRC-32 mismatch"}, err)
+
+	broken = append([]byte{}, archive...)
+	broken[0] = 'X'
+	_, _, err = decodeProgramArchive(bytes.NewReader(broken))
+	assert.Equal(t, ProgramArchiveError{"not a program archive"}, err)
+}
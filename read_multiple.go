@@ -0,0 +1,105 @@
+package fins
+
+import (
+	"context"
+)
+
+// multipleReadMaxItems max items of one multiple memory area read command (CS/CJ CPU units)
+const multipleReadMaxItems = 167
+
+// MultipleReadItem a memory address read by ReadMultiple
+// MemoryArea can be a word area (MemoryAreaDMWord, MemoryAreaTimerCounterPV...),
+// a double word area (MemoryAreaIndexRegisterPV) or a bit area (MemoryAreaDMBit, MemoryAreaTimerCounterCompletionFlag...)
+type MultipleReadItem struct {
+	MemoryArea byte
+	Address    uint16
+	BitOffset  byte // only for bit area
+}
+
+// MultipleReadValue value of a MultipleReadItem
+type MultipleReadValue struct {
+	MultipleReadItem
+	Word       uint16 // value of word area item
+	DoubleWord uint32 // value of double word area item (MemoryAreaIndexRegisterPV)
+	Bit        bool   // value of bit area item
+}
+
+// ReadMultiple Reads words and bits from scattered addresses
+// items are packed into as few multiple memory area read commands as possible
+// and values are returned in the same order as items
+func (c *Client) ReadMultiple(items []MultipleReadItem) ([]MultipleReadValue, error) {
+	return c.ReadMultipleContext(context.Background(), items)
+}
+
+// ReadMultipleContext same as ReadMultiple, stops waiting for the response when ctx is done
+func (c *Client) ReadMultipleContext(ctx context.Context, items []MultipleReadItem) ([]MultipleReadValue, error) {
+	for _, item := range items {
+		if _, err := multipleReadItemSize(item.MemoryArea); err != nil {
+			return nil, err
+		}
+	}
+	return wrapRead(c, func() ([]MultipleReadValue, error) {
+		values := make([]MultipleReadValue, 0, len(items))
+		for start := 0; start < len(items); start += multipleReadMaxItems {
+			end := start + multipleReadMaxItems
+			if end > len(items) {
+				end = len(items)
+			}
+			vs, err := c.readMultiple(ctx, items[start:end])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, vs...)
+		}
+		return values, nil
+	})
+}
+
+func (c *Client) readMultiple(ctx context.Context, items []MultipleReadItem) ([]MultipleReadValue, error) {
+	addrs := make([]memoryAddress, len(items))
+	want := 0
+	for i, item := range items {
+		addr, err := c.encodeMemoryAddress(item.MemoryArea, item.Address, item.BitOffset)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+		size, _ := multipleReadItemSize(item.MemoryArea)
+		want += 1 + size
+	}
+	r, err := c.sendCommandAndCheckResponse(ctx, multipleReadCommand(addrs))
+	if err != nil {
+		return nil, err
+	}
+	if len(r.data) != want {
+		return nil, ResponseLengthError{want: want, got: len(r.data)}
+	}
+
+	values := make([]MultipleReadValue, len(items))
+	data := r.data
+	for i, item := range items {
+		size, _ := multipleReadItemSize(item.MemoryArea)
+		if data[0] != addrs[i].memoryArea {
+			return nil, IncompatibleMemoryAreaError{data[0]}
+		}
+		values[i].MultipleReadItem = item
+		switch size {
+		case 4:
+			values[i].DoubleWord = c.bytesToUint32s(data[1:5])[0]
+		case 2:
+			values[i].Word = c.bytesToUint16s(data[1:3])[0]
+		default:
+			values[i].Bit = data[1]&0x01 > 0
+		}
+		data = data[1+size:]
+	}
+	return values, nil
+}
+
+// multipleReadItemSize data size of an item in multiple memory area read response
+func multipleReadItemSize(memoryArea byte) (int, error) {
+	if size, ok := memoryAreaItemSize[memoryArea]; ok {
+		return size, nil
+	}
+	return 0, IncompatibleMemoryAreaError{memoryArea}
+}
@@ -0,0 +1,52 @@
+package fins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ReadMultiple(t *testing.T) {
+	c, s := newSimulatorClient()
+	defer c.Close()
+
+	assert.Nil(t, c.WriteWords(MemoryAreaDMWord, 100, []uint16{1, 2, 3}))
+	assert.Nil(t, c.WriteWords(MemoryAreaHRWord, 5, []uint16{0x0204}))
+	s.write(memAddr(MemoryAreaCIOWord, 0), 1, []byte{0x00, 0x20})
+	s.write(memAddr(MemoryAreaTimerCounterPV, 10), 1, []byte{0x01, 0x00})
+	s.write(memAddr(MemoryAreaTimerCounterCompletionFlag, 10), 1, []byte{0x01})
+
+	values, err := c.ReadMultiple([]MultipleReadItem{
+		{MemoryArea: MemoryAreaDMWord, Address: 102},
+		{MemoryArea: MemoryAreaCIOBit, Address: 0, BitOffset: 5},
+		{MemoryArea: MemoryAreaHRWord, Address: 5},
+		{MemoryArea: MemoryAreaHRBit, Address: 5, BitOffset: 2},
+		{MemoryArea: MemoryAreaHRBit, Address: 5, BitOffset: 3},
+		{MemoryArea: MemoryAreaTimerCounterPV, Address: 10},
+		{MemoryArea: MemoryAreaTimerCounterCompletionFlag, Address: 10},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(3), values[0].Word)
+	assert.Equal(t, true, values[1].Bit)
+	assert.Equal(t, uint16(0x0204), values[2].Word)
+	assert.Equal(t, true, values[3].Bit)
+	assert.Equal(t, false, values[4].Bit)
+	assert.Equal(t, uint16(0x0100), values[5].Word)
+	assert.Equal(t, true, values[6].Bit)
+	assert.Equal(t, MultipleReadItem{MemoryArea: MemoryAreaHRWord, Address: 5}, values[2].MultipleReadItem)
+
+	// 400 items are split into 3 frames
+	items := make([]MultipleReadItem, 400)
+	for i := range items {
+		items[i] = MultipleReadItem{MemoryArea: MemoryAreaDMWord, Address: uint16(100 + i%3)}
+	}
+	values, err = c.ReadMultiple(items)
+	assert.Nil(t, err)
+	assert.Len(t, values, 400)
+	for i, v := range values {
+		assert.Equal(t, uint16(1+i%3), v.Word)
+	}
+
+	_, err = c.ReadMultiple([]MultipleReadItem{{MemoryArea: 0xff}})
+	assert.Equal(t, IncompatibleMemoryAreaError{0xff}, err)
+}
@@ -0,0 +1,955 @@
+package fins
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// DmAreaSize number of words in simulator DM area
+const DmAreaSize = 32768
+
+// simulatorEMBanks EM banks of simulator, bank 0 is the current bank
+const simulatorEMBanks = 13
+
+// simulatorWordAreaSize items of each word or double word memory area in simulator, same as CS/CJ CPU units
+var simulatorWordAreaSize = map[byte]int{
+	MemoryAreaCIOWord:         6144,
+	MemoryAreaWRWord:          512,
+	MemoryAreaHRWord:          1536,
+	MemoryAreaARWord:          960,
+	MemoryAreaDMWord:          DmAreaSize,
+	MemoryAreaTimerCounterPV:  0x9000, // timers 0x0000-0x0FFF, counters 0x8000-0x8FFF
+	MemoryAreaDataRegisterPV:  16,
+	MemoryAreaIndexRegisterPV: 16,
+}
+
+// simulatorBitArea bit memory area -> word memory area it is stored in
+var simulatorBitArea = map[byte]byte{
+	MemoryAreaCIOBit: MemoryAreaCIOWord,
+	MemoryAreaWRBit:  MemoryAreaWRWord,
+	MemoryAreaHRBit:  MemoryAreaHRWord,
+	MemoryAreaARBit:  MemoryAreaARWord,
+	MemoryAreaDMBit:  MemoryAreaDMWord,
+
+	MemoryAreaEMCurrentBankBit: MemoryAreaEMCurrentBankWord,
+}
+
+// simulatorFlagAreaSize flags of each flag memory area, one flag per address
+var simulatorFlagAreaSize = map[byte]int{
+	MemoryAreaTimerCounterCompletionFlag:   0x9000,
+	MemoryAreaTaskBit:                      32,
+	MemoryAreaTaskStatus:                   32,
+	MemoryAreaClockPulsesConditionFlagsBit: 16,
+}
+
+func init() {
+	for bank := byte(0); bank < simulatorEMBanks; bank++ {
+		simulatorWordAreaSize[MemoryAreaEM0Word+bank] = 32768
+		simulatorBitArea[MemoryAreaEM0Bit+bank] = MemoryAreaEM0Word + bank
+	}
+}
+
+// simulatorCommandModes operating modes in which a command can be executed, others can be executed in any mode
+// I/O memory can be written in any mode, program and parameter areas only in PROGRAM mode
+var simulatorCommandModes = map[uint16][]byte{
+	CommandCodeParameterAreaWrite:   {OperatingModeProgram},
+	CommandCodeParameterAreaClear:   {OperatingModeProgram},
+	CommandCodeProgramAreaWrite:     {OperatingModeProgram},
+	CommandCodeProgramAreaClear:     {OperatingModeProgram},
+	CommandCodeForcedSetReset:       {OperatingModeProgram, OperatingModeMonitor},
+	CommandCodeForcedSetResetCancel: {OperatingModeProgram, OperatingModeMonitor},
+}
+
+const defaultSimulatorCycleTime = 10 * time.Millisecond
+
+// simulatorErrorLogSize records kept in simulator error log, the oldest one is dropped when it is full
+const simulatorErrorLogSize = 20
+
+// simulatorAccessRightCommands commands refused while another device holds the access right
+var simulatorAccessRightCommands = map[uint16]struct{}{
+	CommandCodeRun:                  {},
+	CommandCodeStop:                 {},
+	CommandCodeParameterAreaWrite:   {},
+	CommandCodeParameterAreaClear:   {},
+	CommandCodeProgramAreaWrite:     {},
+	CommandCodeProgramAreaClear:     {},
+	CommandCodeForcedSetReset:       {},
+	CommandCodeForcedSetResetCancel: {},
+}
+
+// simulator the PLC emulated by UDPServer and TCPServer
+// it is just for test. don't use in production
+type simulator struct {
+	commLogger
+	m     sync.Mutex      // UDPServer handles one frame at a time but TCPServer serves many connections
+	words map[byte][]byte // word memory area -> big endian words
+	flags map[byte][]byte // flag memory area -> one byte per flag
+	mode  byte            // operating mode, PROGRAM after power on
+	// memory area codes and address layout of commands
+	dialect Dialect
+	// max words read or written by one memory area read or write frame
+	maxWordsPerFrame int
+	// errors reported by CPU unit status read
+	fatalErrorFlags    uint16
+	nonFatalErrorFlags uint16
+	errorCode          uint16
+	errorMessage       string
+	unitData           CPUUnitData   // answered to CPU unit data read
+	clockOffset        time.Duration // PLC clock - host clock
+	cycleTime          CycleTime     // statistics since last initialize
+	errorLog           []ErrorLogRecord
+	accessRight        *DeviceAddress         // device holding the access right, nil if nobody
+	forced             map[memoryAddress]byte // forced bit -> forced status
+	messages           [8]string              // messages set by MSG instructions
+	disks              map[uint16]*simulatorDisk
+	program            []byte                   // user program
+	parameters         map[ParameterArea][]byte // parameter area -> big endian words
+}
+
+func (s *simulator) initMemory() {
+	s.words = map[byte][]byte{}
+	for area, size := range simulatorWordAreaSize {
+		s.words[area] = make([]byte, size*memoryAreaItemSize[area])
+	}
+	s.words[MemoryAreaEMCurrentBankWord] = s.words[MemoryAreaEM0Word]
+	s.flags = map[byte][]byte{}
+	for area, size := range simulatorFlagAreaSize {
+		s.flags[area] = make([]byte, size)
+	}
+	s.forced = map[memoryAddress]byte{}
+	s.disks = newSimulatorDisks()
+	s.parameters = map[ParameterArea][]byte{}
+	for area, words := range parameterAreaWords {
+		s.parameters[area] = make([]byte, int(words)*2)
+	}
+	s.mode = OperatingModeProgram
+	s.maxWordsPerFrame = DefaultMaxWordsPerFrame
+	s.cycleTime = CycleTime{defaultSimulatorCycleTime, defaultSimulatorCycleTime, defaultSimulatorCycleTime}
+	s.unitData = CPUUnitData{
+		Model:            "CJ2M-CPU33",
+		Version:          "02.00",
+		ProgramAreaSize:  20,
+		IOMSize:          23,
+		DMWords:          DmAreaSize,
+		TimerCounterSize: 8,
+	}
+	s.SetReadPacketErrorLogger(stdoutLoggerInstance)
+}
+
+// handleRequestPacket decodes a FINS request frame and returns the encoded response frame
+func (s *simulator) handleRequestPacket(reqPacket []byte) []byte {
+	req := decodeRequest(reqPacket)
+	resp := s.handler(req)
+	return encodeResponse(resp)
+}
+
+func (s *simulator) handler(r request) response {
+	s.m.Lock()
+	defer s.m.Unlock()
+	var endCode uint16
+	var data []byte
+	if endCode = s.checkMode(r.commandCode); endCode != EndCodeNormalCompletion {
+		return response{defaultResponseHeader(r.header), r.commandCode, endCode, data}
+	}
+	if data, endCode = s.checkAccessRight(r); endCode != EndCodeNormalCompletion {
+		return response{defaultResponseHeader(r.header), r.commandCode, endCode, data}
+	}
+	switch r.commandCode {
+	case CommandCodeMemoryAreaRead:
+		data, endCode = s.memoryAreaRead(r.data)
+	case CommandCodeMemoryAreaWrite:
+		endCode = s.memoryAreaWrite(r.data)
+	case CommandCodeMemoryAreaFill:
+		endCode = s.memoryAreaFill(r.data)
+	case CommandCodeMemoryAreaTransfer:
+		endCode = s.memoryAreaTransfer(r.data)
+	case CommandCodeMultipleMemoryAreaRead:
+		data, endCode = s.multipleMemoryAreaRead(r.data)
+	case CommandCodeParameterAreaRead:
+		data, endCode = s.parameterAreaRead(r.data)
+	case CommandCodeParameterAreaWrite:
+		endCode = s.parameterAreaWrite(r.data)
+	case CommandCodeParameterAreaClear:
+		endCode = s.parameterAreaClear(r.data)
+	case CommandCodeProgramAreaRead:
+		data, endCode = s.programAreaRead(r.data)
+	case CommandCodeProgramAreaWrite:
+		data, endCode = s.programAreaWrite(r.data)
+	case CommandCodeProgramAreaClear:
+		endCode = s.programAreaClear(r.data)
+	case CommandCodeRun:
+		endCode = s.run(r.data)
+	case CommandCodeStop:
+		endCode = s.stop(r.data)
+	case CommandCodeAccessRightAcquire:
+		data, endCode = s.accessRightAcquire(r.header.src, r.data)
+	case CommandCodeAccessRightForcedAcquire:
+		endCode = s.accessRightForcedAcquire(r.header.src, r.data)
+	case CommandCodeAccessRightRelease:
+		endCode = s.accessRightRelease(r.header.src, r.data)
+	case CommandCodeCPUUnitDataRead:
+		data, endCode = s.cpuUnitDataRead(r.data)
+	case CommandCodeCycleTimeRead:
+		data, endCode = s.cycleTimeRead(r.data)
+	case CommandCodeErrorClear:
+		endCode = s.errorClear(r.data)
+	case CommandCodeErrorLogRead:
+		data, endCode = s.errorLogRead(r.data)
+	case CommandCodeErrorLogClear:
+		endCode = s.errorLogClear(r.data)
+	case CommandCodeFileNameRead:
+		data, endCode = s.fileNameRead(r.data)
+	case CommandCodeSingleFileRead:
+		data, endCode = s.singleFileRead(r.data)
+	case CommandCodeSingleFileWrite:
+		endCode = s.singleFileWrite(r.data)
+	case CommandCodeFileDelete:
+		data, endCode = s.fileDelete(r.data)
+	case CommandCodeFileCopy:
+		endCode = s.fileCopy(r.data)
+	case CommandCodeFileNameChange:
+		endCode = s.fileNameChange(r.data)
+	case CommandCodeDirectoryCreateDelete:
+		endCode = s.directoryCreateDelete(r.data)
+	case CommandCodeForcedSetReset:
+		endCode = s.forcedSetReset(r.data)
+	case CommandCodeForcedSetResetCancel:
+		endCode = s.forcedSetResetCancel(r.data)
+	case CommandCodeMessageReadClear:
+		data, endCode = s.messageReadClear(r.data)
+	case CommandCodeClockRead:
+		data, endCode = s.clockRead(r.data)
+	case CommandCodeClockWrite:
+		endCode = s.clockWrite(r.data)
+	case CommandCodeCPUUnitStatusRead:
+		data, endCode = s.cpuUnitStatusRead(r.data)
+	default:
+		s.printFinsPacketError("Command code is not supported: 0x%04x\n", r.commandCode)
+		endCode = EndCodeNotSupportedByModelVersion
+	}
+	return response{defaultResponseHeader(r.header), r.commandCode, endCode, data}
+}
+
+// checkMode checks if command can be executed in current operating mode
+func (s *simulator) checkMode(commandCode uint16) uint16 {
+	modes, ok := simulatorCommandModes[commandCode]
+	if !ok {
+		return EndCodeNormalCompletion
+	}
+	for _, mode := range modes {
+		if mode == s.mode {
+			return EndCodeNormalCompletion
+		}
+	}
+	switch s.mode {
+	case OperatingModeRun:
+		return EndCodeNotExecutableInCurrentModeWrongPLCModeInRun
+	case OperatingModeMonitor:
+		return EndCodeNotExecutableInCurrentModeWrongPLCModeInMonitor
+	default:
+		return EndCodeNotExecutableInCurrentModeWrongPLCModeInProgram
+	}
+}
+
+// checkAccessRight returns 0x3001 and the holder address if another device holds the access right
+func (s *simulator) checkAccessRight(r request) ([]byte, uint16) {
+	if _, ok := simulatorAccessRightCommands[r.commandCode]; !ok {
+		return nil, EndCodeNormalCompletion
+	}
+	if s.accessRight == nil || *s.accessRight == r.header.src {
+		return nil, EndCodeNormalCompletion
+	}
+	return s.accessRightHolder(), EndCodeAccessWriteErrorNoAccessRight
+}
+
+func (s *simulator) accessRightHolder() []byte {
+	return []byte{s.accessRight.network, s.accessRight.node, s.accessRight.unit}
+}
+
+func (s *simulator) accessRightAcquire(src DeviceAddress, data []byte) ([]byte, uint16) {
+	if endCode := checkProgramNumberData(data); endCode != EndCodeNormalCompletion {
+		return nil, endCode
+	}
+	if s.accessRight != nil && *s.accessRight != src {
+		return s.accessRightHolder(), EndCodeAccessWriteErrorNoAccessRight
+	}
+	s.accessRight = &src
+	return nil, EndCodeNormalCompletion
+}
+
+func (s *simulator) accessRightForcedAcquire(src DeviceAddress, data []byte) uint16 {
+	if endCode := checkProgramNumberData(data); endCode != EndCodeNormalCompletion {
+		return endCode
+	}
+	s.accessRight = &src
+	return EndCodeNormalCompletion
+}
+
+// accessRightRelease releasing access right held by others does nothing
+func (s *simulator) accessRightRelease(src DeviceAddress, data []byte) uint16 {
+	if endCode := checkProgramNumberData(data); endCode != EndCodeNormalCompletion {
+		return endCode
+	}
+	if s.accessRight != nil && *s.accessRight == src {
+		s.accessRight = nil
+	}
+	return EndCodeNormalCompletion
+}
+
+func checkProgramNumberData(data []byte) uint16 {
+	if len(data) < 2 {
+		return EndCodeCommandTooShort
+	}
+	if len(data) > 2 {
+		return EndCodeCommandTooLong
+	}
+	return EndCodeNormalCompletion
+}
+
+// parameterArea decodes area code, beginning word and number of words, returns words of the area and range of the words
+func (s *simulator) parameterArea(data []byte) (words []byte, start, end int, last bool, endCode uint16) {
+	if len(data) < 6 {
+		return nil, 0, 0, false, EndCodeCommandTooShort
+	}
+	words, ok := s.parameters[ParameterArea(binary.BigEndian.Uint16(data[0:2]))]
+	if !ok {
+		return nil, 0, 0, false, EndCodeAreaClassificationMissing
+	}
+	n := binary.BigEndian.Uint16(data[4:6])
+	start = int(binary.BigEndian.Uint16(data[2:4])) * 2
+	end = start + int(n&^parameterAreaLastFlag)*2
+	if end > len(words) {
+		return nil, 0, 0, false, EndCodeAddressRangeExceeded
+	}
+	return words, start, end, n&parameterAreaLastFlag != 0, EndCodeNormalCompletion
+}
+
+func (s *simulator) parameterAreaRead(data []byte) ([]byte, uint16) {
+	words, start, end, last, endCode := s.parameterArea(data)
+	if endCode != EndCodeNormalCompletion {
+		return nil, endCode
+	}
+	if len(data) > 6 {
+		return nil, EndCodeCommandTooLong
+	}
+	if last || end-start > parameterAreaChunkWords*2 {
+		return nil, EndCodeParameterError
+	}
+	resp := append([]byte{}, data...)
+	if end == len(words) {
+		resp[4] |= byte(parameterAreaLastFlag >> 8)
+	}
+	return append(resp, words[start:end]...), EndCodeNormalCompletion
+}
+
+func (s *simulator) parameterAreaWrite(data []byte) uint16 {
+	words, start, end, _, endCode := s.parameterArea(data)
+	if endCode != EndCodeNormalCompletion {
+		return endCode
+	}
+	if len(data) != 6+end-start {
+		return EndCodeElementsDataDontMatch
+	}
+	if end-start > parameterAreaChunkWords*2 {
+		return EndCodeParameterError
+	}
+	copy(words[start:end], data[6:])
+	return EndCodeNormalCompletion
+}
+
+// parameterAreaClear the whole area must be cleared with clear data 0x0000
+func (s *simulator) parameterAreaClear(data []byte) uint16 {
+	words, start, end, _, endCode := s.parameterArea(data)
+	if endCode != EndCodeNormalCompletion {
+		return endCode
+	}
+	if len(data) < 8 {
+		return EndCodeCommandTooShort
+	}
+	if len(data) > 8 {
+		return EndCodeCommandTooLong
+	}
+	if start != 0 || end != len(words) || data[6] != 0 || data[7] != 0 {
+		return EndCodeParameterError
+	}
+	for i := range words {
+		words[i] = 0
+	}
+	return EndCodeNormalCompletion
+}
+
+func (s *simulator) programAreaRead(data []byte) ([]byte, uint16) {
+	if len(data) < 8 {
+		return nil, EndCodeCommandTooShort
+	}
+	if len(data) > 8 {
+		return nil, EndCodeCommandTooLong
+	}
+	offset, length := int(binary.BigEndian.Uint32(data[2:6])), int(binary.BigEndian.Uint16(data[6:8]))
+	if length > programChunkSize {
+		return nil, EndCodeParameterError
+	}
+	if offset > len(s.program) {
+		return nil, EndCodeAddressRangeExceeded
+	}
+	block := s.program[offset:]
+	n := uint16(len(block))
+	if len(block) > length {
+		block, n = block[:length], uint16(length)
+	} else {
+		n |= programLastFlag
+	}
+	resp := append([]byte{}, data[0:6]...)
+	resp = binary.BigEndian.AppendUint16(resp, n)
+	return append(resp, block...), EndCodeNormalCompletion
+}
+
+// programAreaWrite blocks are written in order, a block can overwrite the end of the program
+func (s *simulator) programAreaWrite(data []byte) ([]byte, uint16) {
+	if len(data) < 8 {
+		return nil, EndCodeCommandTooShort
+	}
+	offset := int(binary.BigEndian.Uint32(data[2:6]))
+	length := int(binary.BigEndian.Uint16(data[6:8]) &^ programLastFlag)
+	if len(data) != 8+length {
+		return nil, EndCodeElementsDataDontMatch
+	}
+	if length > programChunkSize {
+		return nil, EndCodeParameterError
+	}
+	if offset > len(s.program) || offset+length > int(s.unitData.ProgramAreaSize)*1024*2 {
+		return nil, EndCodeAddressRangeExceeded
+	}
+	s.program = append(s.program[:offset], data[8:]...)
+	return append([]byte{}, data[0:8]...), EndCodeNormalCompletion
+}
+
+func (s *simulator) programAreaClear(data []byte) uint16 {
+	if len(data) < 3 {
+		return EndCodeCommandTooShort
+	}
+	if len(data) > 3 {
+		return EndCodeCommandTooLong
+	}
+	if data[2] != programClearCode {
+		return EndCodeParameterError
+	}
+	s.program = nil
+	return EndCodeNormalCompletion
+}
+
+func (s *simulator) run(data []byte) uint16 {
+	if len(data) < 2 {
+		return EndCodeCommandTooShort
+	}
+	if len(data) > 3 {
+		return EndCodeCommandTooLong
+	}
+	mode := OperatingModeMonitor // mode can be omitted
+	if len(data) == 3 {
+		mode = data[2]
+	}
+	if mode != OperatingModeMonitor && mode != OperatingModeRun {
+		return EndCodeParameterError
+	}
+	s.mode = mode
+	return EndCodeNormalCompletion
+}
+
+func (s *simulator) stop(data []byte) uint16 {
+	if len(data) > 2 {
+		return EndCodeCommandTooLong
+	}
+	s.mode = OperatingModeProgram
+	return EndCodeNormalCompletion
+}
+
+// SetCPUUnitData sets identity data answered to CPU unit data read
+func (s *simulator) SetCPUUnitData(d CPUUnitData) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.unitData = d
+}
+
+// cpuUnitDataRead no data reads all, 0x00 reads model, version and area data, 0x01 reads unit configuration
+func (s *simulator) cpuUnitDataRead(data []byte) ([]byte, uint16) {
+	if len(data) > 1 {
+		return nil, EndCodeCommandTooLong
+	}
+	all := encodeCPUUnitData(&s.unitData)
+	if len(data) == 0 {
+		return all, EndCodeNormalCompletion
+	}
+	switch data[0] {
+	case 0x00:
+		return all[:cpuUnitDataSize], EndCodeNormalCompletion
+	case 0x01:
+		return all[cpuUnitDataSize:], EndCodeNormalCompletion
+	}
+	return nil, EndCodeParameterError
+}
+
+func (s *simulator) cpuUnitStatusRead(data []byte) ([]byte, uint16) {
+	if len(data) > 0 {
+		return nil, EndCodeCommandTooLong
+	}
+	status := CPUStatus{
+		Status:             CPUStatusRun,
+		Mode:               s.mode,
+		FatalErrorFlags:    s.fatalErrorFlags,
+		NonFatalErrorFlags: s.nonFatalErrorFlags,
+		ErrorCode:          s.errorCode,
+		ErrorMessage:       s.errorMessage,
+	}
+	for n, message := range s.messages {
+		if message != "" {
+			status.MessageFlags |= 1 << n
+		}
+	}
+	if s.mode == OperatingModeProgram {
+		status.Status = CPUStatusStop
+	}
+	return encodeCPUStatus(&status), EndCodeNormalCompletion
+}
+
+// SetCycleTime sets cycle time of the simulated PLC, max and min cycle time are updated too
+func (s *simulator) SetCycleTime(d time.Duration) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.cycleTime.Average = d
+	if d > s.cycleTime.Max {
+		s.cycleTime.Max = d
+	}
+	if d < s.cycleTime.Min {
+		s.cycleTime.Min = d
+	}
+}
+
+func (s *simulator) cycleTimeRead(data []byte) ([]byte, uint16) {
+	if len(data) < 1 {
+		return nil, EndCodeCommandTooShort
+	}
+	if len(data) > 1 {
+		return nil, EndCodeCommandTooLong
+	}
+	switch data[0] {
+	case cycleTimeInitialize:
+		s.cycleTime.Max = s.cycleTime.Average
+		s.cycleTime.Min = s.cycleTime.Average
+		return nil, EndCodeNormalCompletion
+	case cycleTimeRead:
+		return encodeCycleTime(&s.cycleTime), EndCodeNormalCompletion
+	}
+	return nil, EndCodeParameterError
+}
+
+// LogError raises an error in the simulated PLC, it becomes the current error and is added to the error log
+func (s *simulator) LogError(code, details uint16) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.errorCode = code
+	s.errorLog = append(s.errorLog, ErrorLogRecord{code, details, time.Now().Add(s.clockOffset).Truncate(time.Second)})
+	if len(s.errorLog) > simulatorErrorLogSize {
+		s.errorLog = s.errorLog[len(s.errorLog)-simulatorErrorLogSize:]
+	}
+}
+
+func (s *simulator) errorClear(data []byte) uint16 {
+	if len(data) < 2 {
+		return EndCodeCommandTooShort
+	}
+	if len(data) > 2 {
+		return EndCodeCommandTooLong
+	}
+	code := binary.BigEndian.Uint16(data)
+	if code == ErrorCodeAll || code == s.errorCode {
+		s.errorCode = 0
+		s.errorMessage = ""
+		s.fatalErrorFlags = 0
+		s.nonFatalErrorFlags = 0
+	}
+	return EndCodeNormalCompletion
+}
+
+func (s *simulator) errorLogRead(data []byte) ([]byte, uint16) {
+	if len(data) < 4 {
+		return nil, EndCodeCommandTooShort
+	}
+	if len(data) > 4 {
+		return nil, EndCodeCommandTooLong
+	}
+	start := int(binary.BigEndian.Uint16(data[0:2]))
+	count := int(binary.BigEndian.Uint16(data[2:4]))
+	if count == 0 || count > errorLogReadMaxRecords {
+		return nil, EndCodeParameterError
+	}
+	if start > len(s.errorLog) {
+		return nil, EndCodeAddressRangeError
+	}
+	if start+count > len(s.errorLog) {
+		count = len(s.errorLog) - start
+	}
+	resp := make([]byte, 6, 6+count*errorLogRecordSize)
+	binary.BigEndian.PutUint16(resp[0:2], simulatorErrorLogSize)
+	binary.BigEndian.PutUint16(resp[2:4], uint16(len(s.errorLog)))
+	binary.BigEndian.PutUint16(resp[4:6], uint16(count))
+	for _, record := range s.errorLog[start : start+count] {
+		b, err := encodeErrorLogRecord(&record)
+		if err != nil {
+			return nil, EndCodeCPUUnitError
+		}
+		resp = append(resp, b...)
+	}
+	return resp, EndCodeNormalCompletion
+}
+
+func (s *simulator) errorLogClear(data []byte) uint16 {
+	if len(data) > 0 {
+		return EndCodeCommandTooLong
+	}
+	s.errorLog = nil
+	return EndCodeNormalCompletion
+}
+
+func (s *simulator) forcedSetReset(data []byte) uint16 {
+	if len(data) < 2 {
+		return EndCodeCommandTooShort
+	}
+	n := int(binary.BigEndian.Uint16(data[0:2]))
+	if len(data) < 2+n*6 {
+		return EndCodeCommandTooShort
+	}
+	if len(data) > 2+n*6 {
+		return EndCodeCommandTooLong
+	}
+	bits := make([]ForcedBit, n)
+	for i := range bits {
+		item := data[2+i*6 : 8+i*6]
+		addr, ok := s.decodeMemoryAddress(item[2:6])
+		bits[i] = ForcedBit{binary.BigEndian.Uint16(item[0:2]), addr.memoryArea, addr.address, addr.bitOffset}
+		if !ok || checkIsForceableMemoryArea(addr.memoryArea) != nil {
+			return EndCodeAreaClassificationMissing
+		}
+		if _, endCode := s.read(addr, 1); endCode != EndCodeNormalCompletion {
+			return endCode
+		}
+		switch bits[i].Spec {
+		case ForceSpecReset, ForceSpecSet, ForceSpecReleaseOff, ForceSpecReleaseOn, ForceSpecRelease:
+		default:
+			return EndCodeParameterError
+		}
+	}
+	for _, bit := range bits {
+		addr := memAddrWithBitOffset(bit.MemoryArea, bit.Address, bit.BitOffset)
+		switch bit.Spec {
+		case ForceSpecReset, ForceSpecSet:
+			s.forced[addr] = byte(bit.Spec)
+			s.writeMemory(addr, 1, []byte{byte(bit.Spec)})
+		case ForceSpecReleaseOff, ForceSpecReleaseOn:
+			delete(s.forced, addr)
+			s.writeMemory(addr, 1, []byte{byte(bit.Spec & 0x01)})
+		case ForceSpecRelease:
+			delete(s.forced, addr)
+		}
+	}
+	return EndCodeNormalCompletion
+}
+
+func (s *simulator) forcedSetResetCancel(data []byte) uint16 {
+	if len(data) > 0 {
+		return EndCodeCommandTooLong
+	}
+	s.forced = map[memoryAddress]byte{}
+	return EndCodeNormalCompletion
+}
+
+// SetDialect sets memory area codes and address layout of commands, DialectCSCJ by default
+func (s *simulator) SetDialect(d Dialect) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.dialect = d
+}
+
+// SetMaxWordsPerFrame sets max words read or written by one frame, DefaultMaxWordsPerFrame by default
+func (s *simulator) SetMaxWordsPerFrame(words uint16) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.maxWordsPerFrame = int(words)
+}
+
+// SetMessage sets message n like a MSG instruction, "" clears it
+func (s *simulator) SetMessage(n byte, message string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.messages[n%8] = message
+}
+
+func (s *simulator) messageReadClear(data []byte) ([]byte, uint16) {
+	if len(data) < 2 {
+		return nil, EndCodeCommandTooShort
+	}
+	if len(data) > 2 {
+		return nil, EndCodeCommandTooLong
+	}
+	parameter := binary.BigEndian.Uint16(data)
+	mask := byte(parameter)
+	switch parameter & 0xff00 {
+	case messageRead:
+		resp := append([]byte{}, data...)
+		for n, message := range s.messages {
+			if mask&(1<<n) != 0 {
+				b := make([]byte, messageSize)
+				copy(b, message)
+				resp = append(resp, b...)
+			}
+		}
+		return resp, EndCodeNormalCompletion
+	case messageClear:
+		for n := range s.messages {
+			if mask&(1<<n) != 0 {
+				s.messages[n] = ""
+			}
+		}
+		return nil, EndCodeNormalCompletion
+	case messageFALSNameRead:
+		if mask != 0 {
+			return nil, EndCodeParameterError
+		}
+		resp := make([]byte, falsNameSize)
+		if n, ok := falsNumber(s.errorCode); ok {
+			binary.BigEndian.PutUint16(resp[0:2], n)
+			copy(resp[2:], s.errorMessage)
+		}
+		return resp, EndCodeNormalCompletion
+	}
+	return nil, EndCodeParameterError
+}
+
+// falsNumber FAL errors are 0x4101-0x42FF and FALS errors are 0xC101-0xC2FF for FAL/FALS number 1-511
+func falsNumber(errorCode uint16) (uint16, bool) {
+	kind, code := errorCode&0xc000, errorCode&0x3fff
+	if (kind == 0x4000 || kind == 0xc000) && code > 0x0100 && code <= 0x02ff {
+		return code - 0x0100, true
+	}
+	return 0, false
+}
+
+func (s *simulator) clockRead(data []byte) ([]byte, uint16) {
+	if len(data) > 0 {
+		return nil, EndCodeCommandTooLong
+	}
+	clock, err := encodeClock(time.Now().Add(s.clockOffset))
+	if err != nil {
+		return nil, EndCodeCPUUnitError
+	}
+	return clock, EndCodeNormalCompletion
+}
+
+// clockWrite second and day of week can be omitted, day of week is ignored
+func (s *simulator) clockWrite(data []byte) uint16 {
+	if len(data) < 5 {
+		return EndCodeCommandTooShort
+	}
+	if len(data) > 7 {
+		return EndCodeCommandTooLong
+	}
+	clock := append(append([]byte{}, data[:5]...), 0)
+	if len(data) > 5 {
+		clock[5] = data[5]
+	}
+	t, err := decodeClock(clock)
+	if err != nil {
+		return EndCodeParameterError
+	}
+	s.clockOffset = time.Until(*t)
+	return EndCodeNormalCompletion
+}
+
+func (s *simulator) memoryAreaRead(data []byte) ([]byte, uint16) {
+	if len(data) < 6 {
+		return nil, EndCodeCommandTooShort
+	}
+	if len(data) > 6 {
+		return nil, EndCodeCommandTooLong
+	}
+	addr, ok := s.decodeMemoryAddress(data[:4])
+	if !ok {
+		return nil, EndCodeAreaClassificationMissing
+	}
+	ic := binary.BigEndian.Uint16(data[4:6]) // Item count
+	if int(ic)*memoryAreaItemSize[addr.memoryArea] > s.maxWordsPerFrame*2 {
+		return nil, EndCodeResponseTooBig
+	}
+	return s.read(addr, ic)
+}
+
+func (s *simulator) memoryAreaWrite(data []byte) uint16 {
+	if len(data) < 6 {
+		return EndCodeCommandTooShort
+	}
+	addr, ok := s.decodeMemoryAddress(data[:4])
+	if !ok {
+		return EndCodeAreaClassificationMissing
+	}
+	if _, ok := memoryAreaReadOnly[addr.memoryArea]; ok {
+		return EndCodeWriteNotPossibleReadOnly
+	}
+	if len(data)-6 > s.maxWordsPerFrame*2 {
+		return EndCodeCommandTooLong
+	}
+	ic := binary.BigEndian.Uint16(data[4:6]) // Item count
+	return s.write(addr, ic, data[6:])
+}
+
+func (s *simulator) memoryAreaFill(data []byte) uint16 {
+	if len(data) < 8 {
+		return EndCodeCommandTooShort
+	}
+	if len(data) > 8 {
+		return EndCodeCommandTooLong
+	}
+	addr, ok := s.decodeMemoryAddress(data[:4])
+	if !ok || checkIsWordMemoryArea(addr.memoryArea) != nil {
+		return EndCodeAreaClassificationMissing
+	}
+	ic := binary.BigEndian.Uint16(data[4:6]) // Item count
+	return s.write(addr, ic, bytes.Repeat(data[6:8], int(ic)))
+}
+
+func (s *simulator) memoryAreaTransfer(data []byte) uint16 {
+	if len(data) < 10 {
+		return EndCodeCommandTooShort
+	}
+	if len(data) > 10 {
+		return EndCodeCommandTooLong
+	}
+	src, srcOk := s.decodeMemoryAddress(data[:4])
+	dst, dstOk := s.decodeMemoryAddress(data[4:8])
+	if !srcOk || !dstOk || checkIsWordMemoryArea(src.memoryArea) != nil || checkIsWordMemoryArea(dst.memoryArea) != nil {
+		return EndCodeAreaClassificationMissing
+	}
+	ic := binary.BigEndian.Uint16(data[8:10]) // Item count
+	value, endCode := s.read(src, ic)
+	if endCode != EndCodeNormalCompletion {
+		return endCode
+	}
+	return s.write(dst, ic, value)
+}
+
+func (s *simulator) multipleMemoryAreaRead(data []byte) ([]byte, uint16) {
+	if len(data) < 4 {
+		return nil, EndCodeCommandTooShort
+	}
+	if len(data)%4 != 0 {
+		return nil, EndCodeCommandFormatError
+	}
+	if len(data)/4 > multipleReadMaxItems {
+		return nil, EndCodeCommandTooLong
+	}
+	var result []byte
+	for i := 0; i < len(data); i += 4 {
+		addr, ok := s.decodeMemoryAddress(data[i : i+4])
+		if !ok {
+			return nil, EndCodeAreaClassificationMissing
+		}
+		value, endCode := s.read(addr, 1)
+		if endCode != EndCodeNormalCompletion {
+			return nil, endCode
+		}
+		result = append(result, data[i])
+		result = append(result, value...)
+	}
+	return result, EndCodeNormalCompletion
+}
+
+// decodeMemoryAddress memory address of command in CS/CJ mode, false if the area is not in the dialect of simulator
+func (s *simulator) decodeMemoryAddress(data []byte) (memoryAddress, bool) {
+	return s.dialect.decodeMemoryAddress(decodeMemoryAddress(data))
+}
+
+// read reads ic items at addr, 4 bytes per double word, 2 bytes per word, 1 byte per bit or flag
+func (s *simulator) read(addr memoryAddress, ic uint16) ([]byte, uint16) {
+	if words, ok := s.words[addr.memoryArea]; ok {
+		size := memoryAreaItemSize[addr.memoryArea]
+		start, end := int(addr.address)*size, (int(addr.address)+int(ic))*size
+		if end > len(words) { // Check address boundary
+			return nil, EndCodeAddressRangeExceeded
+		}
+		return append([]byte{}, words[start:end]...), EndCodeNormalCompletion
+	}
+	if wordArea, ok := simulatorBitArea[addr.memoryArea]; ok {
+		words := s.words[wordArea]
+		start := int(addr.address)*16 + int(addr.bitOffset)
+		if start+int(ic) > len(words)*8 { // Check address boundary
+			return nil, EndCodeAddressRangeExceeded
+		}
+		result := make([]byte, ic)
+		for i := range result {
+			result[i] = getBit(words, start+i)
+		}
+		return result, EndCodeNormalCompletion
+	}
+	if flags, ok := s.flags[addr.memoryArea]; ok {
+		if int(addr.address)+int(ic) > len(flags) { // Check address boundary
+			return nil, EndCodeAddressRangeExceeded
+		}
+		return append([]byte{}, flags[addr.address:int(addr.address)+int(ic)]...), EndCodeNormalCompletion
+	}
+	s.printFinsPacketError("Memory area is not supported: 0x%02x\n", addr.memoryArea)
+	return nil, EndCodeAreaClassificationMissing
+}
+
+// write writes ic items to addr, 4 bytes per double word, 2 bytes per word, 1 byte per bit or flag
+// forced bits keep their forced status
+func (s *simulator) write(addr memoryAddress, ic uint16, data []byte) uint16 {
+	endCode := s.writeMemory(addr, ic, data)
+	for bit, v := range s.forced {
+		s.writeMemory(bit, 1, []byte{v})
+	}
+	return endCode
+}
+
+func (s *simulator) writeMemory(addr memoryAddress, ic uint16, data []byte) uint16 {
+	if words, ok := s.words[addr.memoryArea]; ok {
+		size := memoryAreaItemSize[addr.memoryArea]
+		start, end := int(addr.address)*size, (int(addr.address)+int(ic))*size
+		if len(data) != end-start {
+			return EndCodeElementsDataDontMatch
+		}
+		if end > len(words) { // Check address boundary
+			return EndCodeAddressRangeExceeded
+		}
+		copy(words[start:end], data)
+		return EndCodeNormalCompletion
+	}
+	if wordArea, ok := simulatorBitArea[addr.memoryArea]; ok {
+		words := s.words[wordArea]
+		start := int(addr.address)*16 + int(addr.bitOffset)
+		if len(data) != int(ic) {
+			return EndCodeElementsDataDontMatch
+		}
+		if start+int(ic) > len(words)*8 { // Check address boundary
+			return EndCodeAddressRangeExceeded
+		}
+		for i, b := range data {
+			setBit(words, start+i, b)
+		}
+		return EndCodeNormalCompletion
+	}
+	if flags, ok := s.flags[addr.memoryArea]; ok {
+		if len(data) != int(ic) {
+			return EndCodeElementsDataDontMatch
+		}
+		if int(addr.address)+int(ic) > len(flags) { // Check address boundary
+			return EndCodeAddressRangeExceeded
+		}
+		for i, b := range data {
+			flags[int(addr.address)+i] = b & 0x01
+		}
+		return EndCodeNormalCompletion
+	}
+	s.printFinsPacketError("Memory area is not supported: 0x%02x\n", addr.memoryArea)
+	return EndCodeAreaClassificationMissing
+}
@@ -0,0 +1,366 @@
+package fins
+
+import (
+	"encoding/binary"
+	"sort"
+	"strings"
+	"time"
+)
+
+// simulatorDisk in-memory file device of simulator, files are keyed by absolute path like \BACKUP\DM.IOM
+type simulatorDisk struct {
+	label    string
+	capacity uint32
+	files    map[string]*simulatorFile
+}
+
+type simulatorFile struct {
+	dir     bool
+	data    []byte
+	modTime time.Time
+}
+
+func newSimulatorDisks() map[uint16]*simulatorDisk {
+	return map[uint16]*simulatorDisk{
+		DiskMemoryCard:   {label: "MEMCARD", capacity: 128 << 20, files: map[string]*simulatorFile{`\`: {dir: true}}},
+		DiskEMFileMemory: {label: "EMFILE", capacity: 1 << 20, files: map[string]*simulatorFile{`\`: {dir: true}}},
+	}
+}
+
+func (d *simulatorDisk) used() uint32 {
+	var n uint32
+	for _, f := range d.files {
+		n += uint32(len(f.data))
+	}
+	return n
+}
+
+func (d *simulatorDisk) isDir(path string) bool {
+	f, ok := d.files[path]
+	return ok && f.dir
+}
+
+func (d *simulatorDisk) isFile(path string) bool {
+	f, ok := d.files[path]
+	return ok && !f.dir
+}
+
+// children sorted paths of files and directories in dir
+func (d *simulatorDisk) children(dir string) []string {
+	var paths []string
+	for path := range d.files {
+		if path != `\` && filePathDir(path) == dir {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func joinFilePath(dir, name string) string {
+	if dir == `\` {
+		return dir + name
+	}
+	return dir + `\` + name
+}
+
+func filePathDir(path string) string {
+	i := strings.LastIndexByte(path, '\\')
+	if i == 0 {
+		return `\`
+	}
+	return path[:i]
+}
+
+// commandReader reads fields of command data, short is set if data runs out
+type commandReader struct {
+	data  []byte
+	short bool
+}
+
+func (r *commandReader) bytes(n int) []byte {
+	if len(r.data) < n {
+		r.short, r.data = true, nil
+		return make([]byte, n)
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *commandReader) uint16() uint16 {
+	return binary.BigEndian.Uint16(r.bytes(2))
+}
+
+func (r *commandReader) uint32() uint32 {
+	return binary.BigEndian.Uint32(r.bytes(4))
+}
+
+func (r *commandReader) directory() string {
+	return strings.ToUpper(string(r.bytes(int(r.uint16()))))
+}
+
+func (r *commandReader) fileName() string {
+	return decodeFileName(r.bytes(fileNameSize))
+}
+
+// endCode EndCodeCommandTooShort or EndCodeCommandTooLong if all data is not read exactly
+func (r *commandReader) endCode() uint16 {
+	if r.short {
+		return EndCodeCommandTooShort
+	}
+	if len(r.data) > 0 {
+		return EndCodeCommandTooLong
+	}
+	return EndCodeNormalCompletion
+}
+
+func (s *simulator) fileNameRead(data []byte) ([]byte, uint16) {
+	r := commandReader{data: data}
+	disk, start, count, dir := r.uint16(), int(r.uint16()), int(r.uint16()), r.directory()
+	if endCode := r.endCode(); endCode != EndCodeNormalCompletion {
+		return nil, endCode
+	}
+	d, ok := s.disks[disk]
+	if !ok {
+		return nil, EndCodeNoSuchDeviceFileDeviceMissing
+	}
+	if !d.isDir(dir) {
+		return nil, EndCodeReadNotPossibleFileMissing
+	}
+	paths := d.children(dir)
+	if start > len(paths) {
+		return nil, EndCodeParameterError
+	}
+	paths = paths[start:]
+	if len(paths) > count {
+		paths = paths[:count]
+	}
+
+	resp := make([]byte, diskDataSize+2, diskDataSize+2+len(paths)*fileDataSize)
+	copy(resp[0:12], d.label)
+	binary.BigEndian.PutUint32(resp[16:20], d.capacity)
+	binary.BigEndian.PutUint32(resp[20:24], d.capacity-d.used())
+	binary.BigEndian.PutUint16(resp[24:26], uint16(len(d.files)-1))
+	n := uint16(len(paths))
+	if start+len(paths) == len(d.children(dir)) {
+		n |= fileLastFlag
+	}
+	binary.BigEndian.PutUint16(resp[26:28], n)
+	for _, path := range paths {
+		f := d.files[path]
+		name, _ := encodeFileName(path[strings.LastIndexByte(path, '\\')+1:])
+		resp = append(resp, name...)
+		resp = binary.BigEndian.AppendUint32(resp, encodeFileTime(f.modTime))
+		resp = binary.BigEndian.AppendUint32(resp, uint32(len(f.data)))
+	}
+	return resp, EndCodeNormalCompletion
+}
+
+func (s *simulator) singleFileRead(data []byte) ([]byte, uint16) {
+	r := commandReader{data: data}
+	disk, name, position, length, dir := r.uint16(), r.fileName(), int(r.uint32()), int(r.uint16()), r.directory()
+	if endCode := r.endCode(); endCode != EndCodeNormalCompletion {
+		return nil, endCode
+	}
+	d, ok := s.disks[disk]
+	if !ok {
+		return nil, EndCodeNoSuchDeviceFileDeviceMissing
+	}
+	path := joinFilePath(dir, name)
+	if !d.isFile(path) {
+		return nil, EndCodeReadNotPossibleFileMissing
+	}
+	f := d.files[path]
+	if position > len(f.data) || length > fileChunkSize {
+		return nil, EndCodeParameterError
+	}
+	chunk := f.data[position:]
+	if len(chunk) > length {
+		chunk = chunk[:length]
+	}
+	n := uint16(len(chunk))
+	if position+len(chunk) == len(f.data) {
+		n |= fileLastFlag
+	}
+	resp := make([]byte, 10, 10+len(chunk))
+	binary.BigEndian.PutUint32(resp[0:4], uint32(len(f.data)))
+	binary.BigEndian.PutUint32(resp[4:8], uint32(position))
+	binary.BigEndian.PutUint16(resp[8:10], n)
+	return append(resp, chunk...), EndCodeNormalCompletion
+}
+
+func (s *simulator) singleFileWrite(data []byte) uint16 {
+	r := commandReader{data: data}
+	disk, parameter, name, position, length, dir := r.uint16(), r.uint16(), r.fileName(), int(r.uint32()), int(r.uint16()), r.directory()
+	if r.short {
+		return EndCodeCommandTooShort
+	}
+	if len(r.data) != length {
+		return EndCodeElementsDataDontMatch
+	}
+	d, ok := s.disks[disk]
+	if !ok {
+		return EndCodeNoSuchDeviceFileDeviceMissing
+	}
+	if !d.isDir(dir) {
+		return EndCodeWriteNotPossibleFileMissing
+	}
+	path := joinFilePath(dir, name)
+	if _, exists := d.files[path]; exists && !d.isFile(path) {
+		return EndCodeWriteNotPossibleFileNameAlreadyExists
+	}
+	f := d.files[path]
+	var content []byte
+	switch parameter {
+	case fileWriteNew, fileWriteNewOverwrite:
+		if f != nil && parameter == fileWriteNew {
+			return EndCodeWriteNotPossibleFileNameAlreadyExists
+		}
+		if position != 0 {
+			return EndCodeParameterError
+		}
+		content = append([]byte{}, r.data...)
+	case fileWriteAppend, fileWriteOverwrite:
+		if f == nil {
+			return EndCodeWriteNotPossibleFileMissing
+		}
+		if parameter == fileWriteAppend {
+			position = len(f.data)
+		}
+		if position > len(f.data) {
+			return EndCodeParameterError
+		}
+		content = append(append([]byte{}, f.data[:position]...), r.data...)
+		if position+len(r.data) < len(f.data) {
+			content = append(content, f.data[position+len(r.data):]...)
+		}
+	default:
+		return EndCodeParameterError
+	}
+	used := d.used()
+	if f != nil {
+		used -= uint32(len(f.data))
+	}
+	if used+uint32(len(content)) > d.capacity {
+		return EndCodeWriteNotPossibleCannotRegister
+	}
+	d.files[path] = &simulatorFile{data: content, modTime: time.Now().Add(s.clockOffset)}
+	return EndCodeNormalCompletion
+}
+
+// fileDelete deletes nothing if any file is missing
+func (s *simulator) fileDelete(data []byte) ([]byte, uint16) {
+	r := commandReader{data: data}
+	disk, names := r.uint16(), make([]string, r.uint16())
+	for i := range names {
+		names[i] = r.fileName()
+	}
+	dir := r.directory()
+	if endCode := r.endCode(); endCode != EndCodeNormalCompletion {
+		return nil, endCode
+	}
+	d, ok := s.disks[disk]
+	if !ok {
+		return nil, EndCodeNoSuchDeviceFileDeviceMissing
+	}
+	for _, name := range names {
+		if !d.isFile(joinFilePath(dir, name)) {
+			return nil, EndCodeWriteNotPossibleFileMissing
+		}
+	}
+	for _, name := range names {
+		delete(d.files, joinFilePath(dir, name))
+	}
+	resp := make([]byte, 2)
+	binary.BigEndian.PutUint16(resp, uint16(len(names)))
+	return resp, EndCodeNormalCompletion
+}
+
+func (s *simulator) fileCopy(data []byte) uint16 {
+	r := commandReader{data: data}
+	srcDisk, srcDir, srcName := r.uint16(), r.directory(), r.fileName()
+	dstDisk, dstDir, dstName := r.uint16(), r.directory(), r.fileName()
+	if endCode := r.endCode(); endCode != EndCodeNormalCompletion {
+		return endCode
+	}
+	src, srcOk := s.disks[srcDisk]
+	dst, dstOk := s.disks[dstDisk]
+	if !srcOk || !dstOk {
+		return EndCodeNoSuchDeviceFileDeviceMissing
+	}
+	srcPath, dstPath := joinFilePath(srcDir, srcName), joinFilePath(dstDir, dstName)
+	if !src.isFile(srcPath) || !dst.isDir(dstDir) {
+		return EndCodeWriteNotPossibleFileMissing
+	}
+	if _, exists := dst.files[dstPath]; exists {
+		return EndCodeWriteNotPossibleFileNameAlreadyExists
+	}
+	f := src.files[srcPath]
+	if dst.used()+uint32(len(f.data)) > dst.capacity {
+		return EndCodeWriteNotPossibleCannotRegister
+	}
+	dst.files[dstPath] = &simulatorFile{data: append([]byte{}, f.data...), modTime: f.modTime}
+	return EndCodeNormalCompletion
+}
+
+// fileNameChange files in a renamed directory are moved too
+func (s *simulator) fileNameChange(data []byte) uint16 {
+	r := commandReader{data: data}
+	disk, dir, oldName, newName := r.uint16(), r.directory(), r.fileName(), r.fileName()
+	if endCode := r.endCode(); endCode != EndCodeNormalCompletion {
+		return endCode
+	}
+	d, ok := s.disks[disk]
+	if !ok {
+		return EndCodeNoSuchDeviceFileDeviceMissing
+	}
+	oldPath, newPath := joinFilePath(dir, oldName), joinFilePath(dir, newName)
+	if _, exists := d.files[oldPath]; !exists {
+		return EndCodeWriteNotPossibleFileMissing
+	}
+	if _, exists := d.files[newPath]; exists {
+		return EndCodeWriteNotPossibleFileNameAlreadyExists
+	}
+	for path, f := range d.files {
+		if path == oldPath || strings.HasPrefix(path, oldPath+`\`) {
+			delete(d.files, path)
+			d.files[newPath+path[len(oldPath):]] = f
+		}
+	}
+	return EndCodeNormalCompletion
+}
+
+func (s *simulator) directoryCreateDelete(data []byte) uint16 {
+	r := commandReader{data: data}
+	disk, parameter, dir, name := r.uint16(), r.uint16(), r.directory(), r.fileName()
+	if endCode := r.endCode(); endCode != EndCodeNormalCompletion {
+		return endCode
+	}
+	d, ok := s.disks[disk]
+	if !ok {
+		return EndCodeNoSuchDeviceFileDeviceMissing
+	}
+	path := joinFilePath(dir, name)
+	switch parameter {
+	case directoryCreate:
+		if !d.isDir(dir) {
+			return EndCodeWriteNotPossibleFileMissing
+		}
+		if _, exists := d.files[path]; exists {
+			return EndCodeWriteNotPossibleFileNameAlreadyExists
+		}
+		d.files[path] = &simulatorFile{dir: true, modTime: time.Now().Add(s.clockOffset)}
+	case directoryDelete:
+		if !d.isDir(path) {
+			return EndCodeWriteNotPossibleFileMissing
+		}
+		if len(d.children(path)) > 0 {
+			return EndCodeWriteNotPossibleCannotChange
+		}
+		delete(d.files, path)
+	default:
+		return EndCodeParameterError
+	}
+	return EndCodeNormalCompletion
+}
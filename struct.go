@@ -0,0 +1,525 @@
+package fins
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// structTypes types in fins tag and kind of the field (or array element) they are decoded to
+var structTypes = map[string]reflect.Kind{
+	"bool":    reflect.Bool,
+	"int16":   reflect.Int16,
+	"uint16":  reflect.Uint16,
+	"bcd16":   reflect.Uint16,
+	"int32":   reflect.Int32,
+	"uint32":  reflect.Uint32,
+	"bcd32":   reflect.Uint32,
+	"float32": reflect.Float32,
+	"int64":   reflect.Int64,
+	"uint64":  reflect.Uint64,
+	"float64": reflect.Float64,
+	"string":  reflect.String,
+}
+
+// structDefaultTypes type of a field without type in fins tag
+var structDefaultTypes = map[reflect.Kind]string{
+	reflect.Bool:    "bool",
+	reflect.Int16:   "int16",
+	reflect.Uint16:  "uint16",
+	reflect.Int32:   "int32",
+	reflect.Uint32:  "uint32",
+	reflect.Float32: "float32",
+	reflect.Int64:   "int64",
+	reflect.Uint64:  "uint64",
+	reflect.Float64: "float64",
+	reflect.String:  "string",
+}
+
+// structField a struct field with fins tag, arrays are count elements from addr
+type structField struct {
+	name   string
+	index  int
+	addr   Address
+	typ    string
+	order  WordOrder
+	length int // bytes of string
+	count  int
+}
+
+// elementWords words of each element, 0 for bool
+func (f structField) elementWords() int {
+	switch f.typ {
+	case "int16", "uint16", "bcd16":
+		return 1
+	case "int32", "uint32", "bcd32", "float32":
+		return 2
+	case "int64", "uint64", "float64":
+		return 4
+	case "string":
+		return (f.length + 1) / 2
+	}
+	return 0
+}
+
+// structBit a bit of a bool field, pos is address*16+bit of bits in word areas and address of flags
+type structBit struct {
+	area byte
+	pos  int
+	flag bool
+}
+
+func (b structBit) wordAddress() (byte, int) {
+	area, _ := bitWordMemoryArea(b.area)
+	return area, b.pos / 16
+}
+
+// bit returns i-th bit of bool field f
+func (f structField) bit(i int) structBit {
+	area := f.addr.bitMemoryArea()
+	if _, ok := bitWordMemoryArea(area); ok {
+		return structBit{area, int(f.addr.Address)*16 + int(f.addr.BitOffset) + i, false}
+	}
+	return structBit{area, int(f.addr.Address) + i, true}
+}
+
+// ReadStruct Reads fields of the struct v points to, from addresses given in their fins tags
+// tag format: `fins:"<address>[,<type>][,len=<bytes>][,order=<word order>]"`
+// address is in Omron notation, see ParseAddress. type is one of bool, int16, uint16, bcd16, int32, uint32, bcd32,
+// float32, int64, uint64, float64 and string, by default it is the kind of the field. len is required by string.
+// order overrides the word order of the client for the field. arrays are read from consecutive addresses.
+// Example:
+//
+//	type Recipe struct {
+//		Speed   float32  `fins:"D100"`
+//		Count   uint16   `fins:"D102,bcd16"`
+//		Name    string   `fins:"D110,string,len=16"`
+//		Limits  [2]int32 `fins:"D120,int32,order=ABCD"`
+//		Running bool     `fins:"W0.05"`
+//		Done    bool     `fins:"T10"`
+//	}
+//
+// fields in the same area are read together if they fit in one frame, scattered words and flags are read by
+// multiple memory area read, so the struct is read in as few frames as possible
+func (c *Client) ReadStruct(v any) error {
+	return c.ReadStructContext(context.Background(), v)
+}
+
+// ReadStructContext same as ReadStruct, stops waiting for the response when ctx is done
+func (c *Client) ReadStructContext(ctx context.Context, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return InvalidStructError{fmt.Sprintf("%T", v)}
+	}
+	rv = rv.Elem()
+	fields, err := c.structFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	var ranges []structRange
+	var bits []structBit
+	for _, f := range fields {
+		if f.typ != "bool" {
+			start := int(f.addr.Address)
+			ranges = append(ranges, structRange{f.addr.MemoryArea, start, start + f.count*f.elementWords()})
+			continue
+		}
+		for i := 0; i < f.count; i++ {
+			b := f.bit(i)
+			if b.flag {
+				bits = append(bits, b)
+				continue
+			}
+			area, word := b.wordAddress()
+			ranges = append(ranges, structRange{area, word, word + 1})
+		}
+	}
+	image, err := c.readStructImage(ctx, mergeStructRanges(ranges, c.MaxWordsPerFrame()), bits)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		for i := 0; i < f.count; i++ {
+			ev := structElement(fv, i)
+			if f.typ == "bool" {
+				b := f.bit(i)
+				if b.flag {
+					v, ok := image.flags[b]
+					if !ok {
+						return structNotReadError(f)
+					}
+					ev.SetBool(v)
+				} else {
+					area, word := b.wordAddress()
+					data, ok := image.bytes(area, word, 1)
+					if !ok {
+						return structNotReadError(f)
+					}
+					ev.SetBool(getBit(data, b.pos%16) > 0)
+				}
+				continue
+			}
+			words := f.elementWords()
+			data, ok := image.bytes(f.addr.MemoryArea, int(f.addr.Address)+i*words, words)
+			if !ok {
+				return structNotReadError(f)
+			}
+			if err = c.decodeStructElement(f, data, ev); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteStruct Writes fields of struct v, or the struct v points to, to addresses given in their fins tags
+// see ReadStruct for the tag format. adjacent word fields in the same area are written together,
+// bool fields are written by bit, adjacent bits are written together
+func (c *Client) WriteStruct(v any) error {
+	return c.WriteStructContext(context.Background(), v)
+}
+
+// WriteStructContext same as WriteStruct, stops waiting for the response when ctx is done
+func (c *Client) WriteStructContext(ctx context.Context, v any) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return InvalidStructError{fmt.Sprintf("%T", v)}
+	}
+	fields, err := c.structFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	type wordsWrite struct {
+		area  byte
+		start int
+		data  []byte
+	}
+	type bitsWrite struct {
+		structBit
+		values []bool
+	}
+	var words []wordsWrite
+	var bits []bitsWrite
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		if f.typ == "bool" {
+			for i := 0; i < f.count; i++ {
+				bits = append(bits, bitsWrite{f.bit(i), []bool{structElement(fv, i).Bool()}})
+			}
+			continue
+		}
+		data := make([]byte, 0, f.count*f.elementWords()*2)
+		for i := 0; i < f.count; i++ {
+			b, err := c.encodeStructElement(f, structElement(fv, i))
+			if err != nil {
+				return err
+			}
+			data = append(data, b...)
+		}
+		words = append(words, wordsWrite{f.addr.MemoryArea, int(f.addr.Address), data})
+	}
+
+	sort.SliceStable(words, func(i, j int) bool {
+		return words[i].area < words[j].area || words[i].area == words[j].area && words[i].start < words[j].start
+	})
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		for i+1 < len(words) && words[i+1].area == w.area && words[i+1].start == w.start+len(w.data)/2 {
+			i++
+			w.data = append(w.data, words[i].data...)
+		}
+		if err = c.WriteBytesContext(ctx, w.area, uint16(w.start), w.data); err != nil {
+			return err
+		}
+	}
+
+	sort.SliceStable(bits, func(i, j int) bool {
+		return bits[i].area < bits[j].area || bits[i].area == bits[j].area && bits[i].pos < bits[j].pos
+	})
+	for i := 0; i < len(bits); i++ {
+		b := bits[i]
+		for i+1 < len(bits) && bits[i+1].area == b.area && bits[i+1].pos == b.pos+len(b.values) {
+			i++
+			b.values = append(b.values, bits[i].values...)
+		}
+		if b.flag {
+			err = c.WriteBitsContext(ctx, b.area, uint16(b.pos), 0, b.values)
+		} else {
+			err = c.WriteBitsContext(ctx, b.area, uint16(b.pos/16), byte(b.pos%16), b.values)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// structFields parses fins tags of struct type t, fields without fins tag or with tag "-" are skipped
+func (c *Client) structFields(t reflect.Type) ([]structField, error) {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("fins")
+		if !ok || tag == "-" {
+			continue
+		}
+		if !sf.IsExported() {
+			return nil, StructFieldError{sf.Name, "field is not exported"}
+		}
+		parts := strings.Split(tag, ",")
+		addr, err := ParseAddress(parts[0])
+		if err != nil {
+			return nil, StructFieldError{sf.Name, err.Error()}
+		}
+		ft, count := sf.Type, 1
+		if ft.Kind() == reflect.Array {
+			ft, count = ft.Elem(), ft.Len()
+		}
+		f := structField{name: sf.Name, index: i, addr: addr, typ: structDefaultTypes[ft.Kind()], order: c.WordOrder(), count: count}
+		for _, p := range parts[1:] {
+			key, value, hasValue := strings.Cut(strings.TrimSpace(p), "=")
+			switch {
+			case !hasValue:
+				f.typ = key
+			case key == "len":
+				if f.length, err = strconv.Atoi(value); err != nil || f.length <= 0 {
+					return nil, StructFieldError{sf.Name, "invalid len: " + value}
+				}
+			case key == "order":
+				if f.order, ok = parseWordOrder(value); !ok {
+					return nil, StructFieldError{sf.Name, "invalid word order: " + value}
+				}
+			default:
+				return nil, StructFieldError{sf.Name, "unknown option: " + p}
+			}
+		}
+		if kind, ok := structTypes[f.typ]; !ok || kind != ft.Kind() {
+			return nil, StructFieldError{sf.Name, fmt.Sprintf("type %q doesn't fit field of %s", f.typ, sf.Type)}
+		}
+		if f.typ == "string" && f.length == 0 {
+			return nil, StructFieldError{sf.Name, "len is required by string"}
+		}
+		if f.typ != "bool" && checkIsWordMemoryArea(addr.MemoryArea) != nil {
+			return nil, StructFieldError{sf.Name, fmt.Sprintf("%s is not a word address", addr)}
+		}
+		if f.typ == "bool" && checkIsBitMemoryArea(addr.bitMemoryArea()) != nil {
+			return nil, StructFieldError{sf.Name, fmt.Sprintf("%s has no bits", addr)}
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func parseWordOrder(s string) (WordOrder, bool) {
+	for _, o := range []WordOrder{WordOrderCDAB, WordOrderABCD, WordOrderBADC, WordOrderDCBA} {
+		if strings.EqualFold(o.String(), s) {
+			return o, true
+		}
+	}
+	return 0, false
+}
+
+// structElement i-th element of an array field, or the field itself
+func structElement(fv reflect.Value, i int) reflect.Value {
+	if fv.Kind() == reflect.Array {
+		return fv.Index(i)
+	}
+	return fv
+}
+
+// decodeStructElement decodes words read from the PLC into ev, like ReadWords, ReadInt32s, ReadBCD16s, ReadString...
+func (c *Client) decodeStructElement(f structField, data []byte, ev reflect.Value) error {
+	switch f.typ {
+	case "int16":
+		ev.SetInt(int64(int16(c.bytesToUint16s(data)[0])))
+	case "uint16":
+		ev.SetUint(uint64(c.bytesToUint16s(data)[0]))
+	case "bcd16":
+		x, err := decodeBCDFixed(data)
+		if err != nil {
+			return err
+		}
+		ev.SetUint(x)
+	case "string":
+		data = data[:f.length]
+		if n := bytes.IndexByte(data, 0); n != -1 {
+			data = data[:n]
+		}
+		ev.SetString(string(data))
+	default:
+		b := append([]byte{}, data...)
+		f.order.arrange(b)
+		switch f.typ {
+		case "int32":
+			ev.SetInt(int64(int32(binary.BigEndian.Uint32(b))))
+		case "uint32":
+			ev.SetUint(uint64(binary.BigEndian.Uint32(b)))
+		case "bcd32":
+			x, err := decodeBCDFixed(b)
+			if err != nil {
+				return err
+			}
+			ev.SetUint(x)
+		case "float32":
+			ev.SetFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(b))))
+		case "int64":
+			ev.SetInt(int64(binary.BigEndian.Uint64(b)))
+		case "uint64":
+			ev.SetUint(binary.BigEndian.Uint64(b))
+		case "float64":
+			ev.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(b)))
+		}
+	}
+	return nil
+}
+
+// encodeStructElement encodes ev to words written to the PLC, it is the reverse of decodeStructElement
+func (c *Client) encodeStructElement(f structField, ev reflect.Value) ([]byte, error) {
+	switch f.typ {
+	case "int16":
+		return c.uint16sToBytes([]uint16{uint16(ev.Int())}), nil
+	case "uint16":
+		return c.uint16sToBytes([]uint16{uint16(ev.Uint())}), nil
+	case "bcd16":
+		return encodeBCDFixed(ev.Uint(), 2)
+	case "string":
+		s := ev.String()
+		if len(s) > f.length {
+			return nil, StructFieldError{f.name, fmt.Sprintf("string of %d bytes is longer than len %d", len(s), f.length)}
+		}
+		b := make([]byte, f.elementWords()*2)
+		copy(b, s)
+		return b, nil
+	}
+	b := make([]byte, f.elementWords()*2)
+	switch f.typ {
+	case "int32":
+		binary.BigEndian.PutUint32(b, uint32(ev.Int()))
+	case "uint32":
+		binary.BigEndian.PutUint32(b, uint32(ev.Uint()))
+	case "bcd32":
+		bcd, err := encodeBCDFixed(ev.Uint(), 4)
+		if err != nil {
+			return nil, err
+		}
+		copy(b, bcd)
+	case "float32":
+		binary.BigEndian.PutUint32(b, math.Float32bits(float32(ev.Float())))
+	case "int64":
+		binary.BigEndian.PutUint64(b, uint64(ev.Int()))
+	case "uint64":
+		binary.BigEndian.PutUint64(b, ev.Uint())
+	case "float64":
+		binary.BigEndian.PutUint64(b, math.Float64bits(ev.Float()))
+	}
+	f.order.arrange(b)
+	return b, nil
+}
+
+// structRange words [start, end) of a word area
+type structRange struct {
+	area       byte
+	start, end int
+}
+
+// mergeStructRanges merges ranges of the same area into reads of at most maxWords words
+func mergeStructRanges(ranges []structRange, maxWords int) []structRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].area < ranges[j].area || ranges[i].area == ranges[j].area && ranges[i].start < ranges[j].start
+	})
+	var merged []structRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && merged[n-1].area == r.area {
+			last := &merged[n-1]
+			if r.end <= last.end {
+				continue
+			}
+			if r.end-last.start <= maxWords {
+				last.end = r.end
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// structImage words and flags read by ReadStruct
+type structImage struct {
+	words map[byte][]structSegment
+	flags map[structBit]bool
+}
+
+// structSegment big endian words from start
+type structSegment struct {
+	start int
+	data  []byte
+}
+
+// bytes returns count words from start of area, false if they were not read
+func (m structImage) bytes(area byte, start, count int) ([]byte, bool) {
+	for _, s := range m.words[area] {
+		if start >= s.start && (start+count-s.start)*2 <= len(s.data) {
+			return s.data[(start-s.start)*2 : (start+count-s.start)*2], true
+		}
+	}
+	return nil, false
+}
+
+// structNotReadError field f was not read by the read plan, it is a bug of ReadStruct
+func structNotReadError(f structField) error {
+	return StructFieldError{f.name, fmt.Sprintf("%s was not read, read plan is incomplete", f.addr)}
+}
+
+// readStructImage reads ranges and flags, single word ranges and flags are read by multiple memory area read
+// if there are at least two of them
+func (c *Client) readStructImage(ctx context.Context, ranges []structRange, flags []structBit) (structImage, error) {
+	image := structImage{words: map[byte][]structSegment{}, flags: map[structBit]bool{}}
+	var items []MultipleReadItem
+	singles := 0
+	for _, r := range ranges {
+		if r.end-r.start == 1 {
+			singles++
+		}
+	}
+	for _, r := range ranges {
+		if r.end-r.start == 1 && singles+len(flags) >= 2 {
+			items = append(items, MultipleReadItem{MemoryArea: r.area, Address: uint16(r.start)})
+			continue
+		}
+		data, err := c.ReadBytesContext(ctx, r.area, uint16(r.start), uint16(r.end-r.start))
+		if err != nil {
+			return image, err
+		}
+		image.words[r.area] = append(image.words[r.area], structSegment{r.start, data})
+	}
+	for _, f := range flags {
+		items = append(items, MultipleReadItem{MemoryArea: f.area, Address: uint16(f.pos)})
+	}
+	if len(items) == 0 {
+		return image, nil
+	}
+	values, err := c.ReadMultipleContext(ctx, items)
+	if err != nil {
+		return image, err
+	}
+	for _, v := range values {
+		if checkIsWordMemoryArea(v.MemoryArea) == nil {
+			data := c.uint16sToBytes([]uint16{v.Word})
+			image.words[v.MemoryArea] = append(image.words[v.MemoryArea], structSegment{int(v.Address), data})
+		} else {
+			image.flags[structBit{v.MemoryArea, int(v.Address), true}] = v.Bit
+		}
+	}
+	return image, nil
+}
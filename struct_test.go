@@ -0,0 +1,182 @@
+package fins
+
+import (
+	"encoding/binary"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRecipe struct {
+	Speed   float32  `fins:"D100"`
+	Count   uint16   `fins:"D102,bcd16"`
+	Name    string   `fins:"D110,string,len=5"`
+	Limits  [2]int32 `fins:"D120,int32,order=ABCD"`
+	Total   float64  `fins:"E1_0"`
+	Level   int16    `fins:"H5"`
+	Running bool     `fins:"W0.05"`
+	Flags   [3]bool  `fins:"W0.14"`
+	Done    bool     `fins:"T10"`
+	Step    uint16   `fins:"A200"`
+	Ignored int      `fins:"-"`
+	Note    string
+}
+
+// newCountingSimulatorClient client of simulator, returns command codes of frames sent
+func newCountingSimulatorClient() (*Client, *simulator, func() []uint16) {
+	s := &simulator{}
+	s.initMemory()
+	var m sync.Mutex
+	var codes []uint16
+	c := NewClient(func() (Transport, error) {
+		return newMemoryTransport(func(r request) response {
+			m.Lock()
+			codes = append(codes, r.commandCode)
+			m.Unlock()
+			return s.handler(r)
+		}), nil
+	})
+	return c, s, func() []uint16 {
+		m.Lock()
+		defer m.Unlock()
+		sent := codes
+		codes = nil
+		return sent
+	}
+}
+
+func TestClient_WriteStructReadStruct(t *testing.T) {
+	c, s, sent := newCountingSimulatorClient()
+	defer c.Close()
+
+	want := testRecipe{
+		Speed:   1.5,
+		Count:   1234,
+		Name:    "abc",
+		Limits:  [2]int32{-1, 0x12345678},
+		Total:   15.6,
+		Level:   -3,
+		Running: true,
+		Flags:   [3]bool{true, false, true},
+		Step:    7,
+	}
+	assert.Nil(t, c.WriteStruct(want))
+	// D100-D102 together, D110, D120, E1_0, H5, A200, W0.05, W0.14-W1.00 and T10
+	assert.Len(t, sent(), 9)
+
+	words, err := c.ReadWords(MemoryAreaDMWord, 100, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0x0000, 0x3fc0, 0x1234}, words)
+	words, err = c.ReadWords(MemoryAreaDMWord, 120, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0xffff, 0xffff, 0x1234, 0x5678}, words)
+	words, err = c.ReadWords(MemoryAreaWRWord, 0, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{0x4020, 0x0001}, words)
+	sent()
+
+	s.write(memAddr(MemoryAreaTimerCounterCompletionFlag, 10), 1, []byte{1})
+	want.Done = true
+	var got testRecipe
+	got.Note = "kept"
+	assert.Nil(t, c.ReadStruct(&got))
+	want.Note = "kept"
+	assert.Equal(t, want, got)
+	// D100-D123 in one read, E1_0 and W0-W1 by range, H5, A200 and T10 by multiple read
+	assert.Equal(t, []uint16{
+		CommandCodeMemoryAreaRead, CommandCodeMemoryAreaRead, CommandCodeMemoryAreaRead, CommandCodeMultipleMemoryAreaRead,
+	}, sent())
+}
+
+func TestClient_StructByteOrder(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	// like ReadBCD16s, BCD digits are in PLC order whatever the byte order of words is
+	c.SetByteOrder(binary.LittleEndian)
+	assert.Nil(t, c.WriteBCD16s(MemoryAreaDMWord, 0, []uint16{1234}))
+	var v struct {
+		Count uint16 `fins:"D0,bcd16"`
+		Raw   uint16 `fins:"D0"`
+	}
+	assert.Nil(t, c.ReadStruct(&v))
+	assert.Equal(t, uint16(1234), v.Count)
+	assert.Equal(t, uint16(0x3412), v.Raw, "plain words follow the byte order")
+
+	assert.Nil(t, c.WriteStruct(struct {
+		Count uint16 `fins:"D1,bcd16"`
+	}{5678}))
+	values, err := c.ReadBCD16s(MemoryAreaDMWord, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{5678}, values)
+}
+
+func TestClient_ReadStructErrors(t *testing.T) {
+	c, _ := newSimulatorClient()
+	defer c.Close()
+
+	var recipe testRecipe
+	assert.Equal(t, InvalidStructError{"fins.testRecipe"}, c.ReadStruct(recipe))
+	assert.Equal(t, InvalidStructError{"*int"}, c.ReadStruct(new(int)))
+	assert.Equal(t, InvalidStructError{"int"}, c.WriteStruct(1))
+
+	assert.Equal(t, StructFieldError{"V", "type \"float32\" doesn't fit field of int16"}, c.ReadStruct(&struct {
+		V int16 `fins:"D0,float32"`
+	}{}))
+	assert.Equal(t, StructFieldError{"V", "len is required by string"}, c.ReadStruct(&struct {
+		V string `fins:"D0"`
+	}{}))
+	assert.Equal(t, StructFieldError{"V", "invalid word order: XYZW"}, c.ReadStruct(&struct {
+		V int32 `fins:"D0,order=XYZW"`
+	}{}))
+	assert.Equal(t, StructFieldError{"V", "D0.01 is not a word address"}, c.ReadStruct(&struct {
+		V uint16 `fins:"D0.01"`
+	}{}))
+	assert.Equal(t, StructFieldError{"V", "IR1 has no bits"}, c.ReadStruct(&struct {
+		V bool `fins:"IR1"`
+	}{}))
+	assert.Equal(t, StructFieldError{"V", "DR0 has no bits"}, c.WriteStruct(struct {
+		V [2]bool `fins:"DR0"`
+	}{}))
+	assert.Equal(t, StructFieldError{"V", InvalidAddressError{"X0"}.Error()}, c.ReadStruct(&struct {
+		V uint16 `fins:"X0"`
+	}{}))
+	assert.Equal(t, StructFieldError{"V", "string of 4 bytes is longer than len 3"}, c.WriteStruct(struct {
+		V string `fins:"D0,len=3"`
+	}{"abcd"}))
+	assert.Equal(t, BCDOverflowError{}, c.WriteStruct(struct {
+		V uint16 `fins:"D0,bcd16"`
+	}{10000}))
+}
+
+func Test_mergeStructRanges(t *testing.T) {
+	merged := mergeStructRanges([]structRange{
+		{MemoryAreaDMWord, 2000, 2001},
+		{MemoryAreaDMWord, 0, 2},
+		{MemoryAreaHRWord, 0, 1},
+		{MemoryAreaDMWord, 1, 3},
+		{MemoryAreaDMWord, 500, 501},
+		{MemoryAreaDMWord, 998, 1000},
+	}, 999)
+	assert.Equal(t, []structRange{
+		{MemoryAreaDMWord, 0, 501},
+		{MemoryAreaDMWord, 998, 1000},
+		{MemoryAreaDMWord, 2000, 2001},
+		{MemoryAreaHRWord, 0, 1},
+	}, merged)
+}
+
+func Test_structImage_bytes(t *testing.T) {
+	image := structImage{words: map[byte][]structSegment{
+		MemoryAreaDMWord: {{10, []byte{1, 2, 3, 4}}},
+	}}
+	data, ok := image.bytes(MemoryAreaDMWord, 11, 1)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{3, 4}, data)
+	// words not read are reported instead of decoded as zeros
+	_, ok = image.bytes(MemoryAreaDMWord, 11, 2)
+	assert.False(t, ok)
+	_, ok = image.bytes(MemoryAreaHRWord, 10, 1)
+	assert.False(t, ok)
+}
@@ -0,0 +1,128 @@
+package fins
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// FINS/TCP wraps every FINS frame with a 16 bytes header:
+// "FINS" magic(4) + length(4) + command(4) + error code(4)
+// length counts the bytes after the length field, so it is 8 + len(payload)
+
+var tcpMagic = [4]byte{'F', 'I', 'N', 'S'}
+
+const (
+	tcpHeaderSize = 16
+
+	// tcpMaxPayloadSize FINS frame max size (2012 bytes) with some room
+	tcpMaxPayloadSize = 4096
+)
+
+const (
+	// TCPCommandClientNodeAddressSend FINS/TCP command: client node address data send (client to server)
+	TCPCommandClientNodeAddressSend uint32 = 0
+
+	// TCPCommandServerNodeAddressSend FINS/TCP command: server node address data send (server to client)
+	TCPCommandServerNodeAddressSend uint32 = 1
+
+	// TCPCommandFrameSend FINS/TCP command: FINS frame send
+	TCPCommandFrameSend uint32 = 2
+
+	// TCPCommandFrameSendErrorNotification FINS/TCP command: FINS frame send error notification
+	TCPCommandFrameSendErrorNotification uint32 = 3
+
+	// TCPCommandConnectionConfirmation FINS/TCP command: connection confirmation
+	TCPCommandConnectionConfirmation uint32 = 6
+)
+
+const (
+	// TCPErrorCodeNormal FINS/TCP error code: normal
+	TCPErrorCodeNormal uint32 = 0x00
+
+	// TCPErrorCodeHeaderNotFINS FINS/TCP error code: the header is not 'FINS'
+	TCPErrorCodeHeaderNotFINS uint32 = 0x01
+
+	// TCPErrorCodeDataLengthTooLong FINS/TCP error code: the data length is too long
+	TCPErrorCodeDataLengthTooLong uint32 = 0x02
+
+	// TCPErrorCodeCommandNotSupported FINS/TCP error code: the command is not supported
+	TCPErrorCodeCommandNotSupported uint32 = 0x03
+
+	// TCPErrorCodeAllConnectionsInUse FINS/TCP error code: all connections are in use
+	TCPErrorCodeAllConnectionsInUse uint32 = 0x20
+
+	// TCPErrorCodeNodeAlreadyConnected FINS/TCP error code: the specified node is already connected
+	TCPErrorCodeNodeAlreadyConnected uint32 = 0x21
+
+	// TCPErrorCodeProtectedNode FINS/TCP error code: attempt to access a protected node from an unspecified IP address
+	TCPErrorCodeProtectedNode uint32 = 0x22
+
+	// TCPErrorCodeClientNodeOutOfRange FINS/TCP error code: the client FINS node address is out of range
+	TCPErrorCodeClientNodeOutOfRange uint32 = 0x23
+
+	// TCPErrorCodeSameNodeAddress FINS/TCP error code: the same FINS node address is being used by the client and server
+	TCPErrorCodeSameNodeAddress uint32 = 0x24
+
+	// TCPErrorCodeAllNodeAddressesInUse FINS/TCP error code: all the node addresses available for allocation have been used
+	TCPErrorCodeAllNodeAddressesInUse uint32 = 0x25
+)
+
+// tcpHeader A FINS/TCP header
+type tcpHeader struct {
+	length    uint32
+	command   uint32
+	errorCode uint32
+}
+
+func encodeTCPFrame(command, errorCode uint32, payload []byte) []byte {
+	bytes := make([]byte, tcpHeaderSize, tcpHeaderSize+len(payload))
+	copy(bytes[0:4], tcpMagic[:])
+	binary.BigEndian.PutUint32(bytes[4:8], uint32(8+len(payload)))
+	binary.BigEndian.PutUint32(bytes[8:12], command)
+	binary.BigEndian.PutUint32(bytes[12:16], errorCode)
+	return append(bytes, payload...)
+}
+
+func decodeTCPHeader(bytes []byte) (tcpHeader, error) {
+	if [4]byte{bytes[0], bytes[1], bytes[2], bytes[3]} != tcpMagic {
+		return tcpHeader{}, TCPHeaderError{fmt.Sprintf("magic should be 'FINS', got % X", bytes[0:4])}
+	}
+	h := tcpHeader{
+		length:    binary.BigEndian.Uint32(bytes[4:8]),
+		command:   binary.BigEndian.Uint32(bytes[8:12]),
+		errorCode: binary.BigEndian.Uint32(bytes[12:16]),
+	}
+	if h.length < 8 || h.length-8 > tcpMaxPayloadSize {
+		return tcpHeader{}, TCPHeaderError{fmt.Sprintf("invalid length %d", h.length)}
+	}
+	return h, nil
+}
+
+// readTCPFrame reads one FINS/TCP frame from r. payload is read into buf if it is big enough
+func readTCPFrame(r io.Reader, buf []byte) (tcpHeader, []byte, error) {
+	var hb [tcpHeaderSize]byte
+	if _, err := io.ReadFull(r, hb[:]); err != nil {
+		return tcpHeader{}, nil, err
+	}
+	h, err := decodeTCPHeader(hb[:])
+	if err != nil {
+		return tcpHeader{}, nil, err
+	}
+	n := int(h.length - 8)
+	if len(buf) < n {
+		buf = make([]byte, n)
+	}
+	if _, err = io.ReadFull(r, buf[:n]); err != nil {
+		return tcpHeader{}, nil, err
+	}
+	return h, buf[:n], nil
+}
+
+func encodeNodeAddressData(nodes ...byte) []byte {
+	bytes := make([]byte, 4*len(nodes))
+	for i, node := range nodes {
+		binary.BigEndian.PutUint32(bytes[i*4:i*4+4], uint32(node))
+	}
+	return bytes
+}
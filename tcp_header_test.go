@@ -0,0 +1,28 @@
+package fins
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_encodeTCPFrame(t *testing.T) {
+	frame := encodeTCPFrame(TCPCommandClientNodeAddressSend, TCPErrorCodeNormal, encodeNodeAddressData(0))
+	assert.Equal(t, []byte{
+		0x46, 0x49, 0x4E, 0x53, // FINS
+		0x00, 0x00, 0x00, 0x0C,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}, frame)
+
+	h, payload, err := readTCPFrame(bytes.NewReader(frame), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, tcpHeader{length: 12, command: TCPCommandClientNodeAddressSend}, h)
+	assert.Equal(t, []byte{0, 0, 0, 0}, payload)
+
+	frame[0] = 'X'
+	_, _, err = readTCPFrame(bytes.NewReader(frame), nil)
+	assert.IsType(t, TCPHeaderError{}, err)
+}
@@ -0,0 +1,133 @@
+package fins
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// TCPClient Omron FINS/TCP client
+// this is concurrent safe
+type TCPClient struct {
+	Client
+	localAddr TCPAddress
+	plcAddr   TCPAddress
+}
+
+// NewTCPClient creates a new Omron FINS/TCP client
+// node of localAddr is the client FINS node address to ask for, 0 means let the PLC allocate one
+func NewTCPClient(localAddr, plcAddr TCPAddress) (*TCPClient, error) {
+	if plcAddr.tcpAddress == nil {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("missing address")}
+	}
+	c := &TCPClient{
+		localAddr: localAddr,
+		plcAddr:   plcAddr,
+	}
+	c.init(func() (Transport, error) {
+		return dialTCPTransport(localAddr, plcAddr, time.Duration(c.responseTimeout.Load()), &c.commLogger)
+	})
+	// FINS/TCP is a stream, frames must be read one by one
+	c.SetReadGoroutineNum(1)
+	return c, nil
+}
+
+// tcpTransport FINS/TCP Transport
+type tcpTransport struct {
+	conn          *net.TCPConn
+	local, remote DeviceAddress
+	wm            sync.Mutex // serialize frames written to conn
+	rm            sync.Mutex // serialize frames read from conn
+	rbuf          []byte
+}
+
+// dialTCPTransport connects to the PLC and does the node address handshake
+func dialTCPTransport(localAddr, plcAddr TCPAddress, timeout time.Duration, l *commLogger) (*tcpTransport, error) {
+	conn, er := net.DialTCP("tcp", localAddr.tcpAddress, plcAddr.tcpAddress)
+	if er != nil {
+		return nil, er
+	}
+	if conn == nil {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("dail return nil conn and nil error")}
+	}
+	clientNode, serverNode, er := tcpNodeAddressHandshake(conn, localAddr.deviceAddress.node, timeout, l)
+	if er != nil {
+		conn.Close()
+		return nil, er
+	}
+	return &tcpTransport{
+		conn:   conn,
+		local:  DeviceAddress{localAddr.deviceAddress.network, clientNode, localAddr.deviceAddress.unit},
+		remote: DeviceAddress{plcAddr.deviceAddress.network, serverNode, plcAddr.deviceAddress.unit},
+		rbuf:   make([]byte, tcpMaxPayloadSize),
+	}, nil
+}
+
+// tcpNodeAddressHandshake sends client node address data and returns client and server node addresses from the PLC
+func tcpNodeAddressHandshake(conn *net.TCPConn, node byte, timeout time.Duration, l *commLogger) (clientNode, serverNode byte, err error) {
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+		defer conn.SetDeadline(time.Time{})
+	}
+	reqPacket := encodeTCPFrame(TCPCommandClientNodeAddressSend, TCPErrorCodeNormal, encodeNodeAddressData(node))
+	l.printPacket("write", reqPacket)
+	if _, err = conn.Write(reqPacket); err != nil {
+		return 0, 0, err
+	}
+	h, payload, err := readTCPFrame(conn, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	l.printPacket("read", encodeTCPFrame(h.command, h.errorCode, payload))
+	if h.errorCode != TCPErrorCodeNormal {
+		return 0, 0, TCPErrorCodeError{h.errorCode}
+	}
+	if h.command != TCPCommandServerNodeAddressSend {
+		return 0, 0, TCPUnexpectedCommandError{TCPCommandServerNodeAddressSend, h.command}
+	}
+	if len(payload) != 8 {
+		return 0, 0, ResponseLengthError{want: 8, got: len(payload)}
+	}
+	return payload[3], payload[7], nil
+}
+
+func (t *tcpTransport) Send(frame []byte) error {
+	t.wm.Lock()
+	defer t.wm.Unlock()
+	_, err := t.conn.Write(encodeTCPFrame(TCPCommandFrameSend, TCPErrorCodeNormal, frame))
+	return err
+}
+
+func (t *tcpTransport) Receive(buf []byte) (int, error) {
+	t.rm.Lock()
+	defer t.rm.Unlock()
+	h, payload, err := readTCPFrame(t.conn, t.rbuf)
+	if err != nil {
+		if errors.Is(err, net.ErrClosed) {
+			return 0, err
+		}
+		// frames after a bad or partial one can't be found in the stream, give up the connection
+		t.conn.Close()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, io.EOF // peer closed, maybe in the middle of a frame
+		}
+		return 0, TransportBrokenError{err}
+	}
+	if h.errorCode != TCPErrorCodeNormal {
+		return 0, TCPErrorCodeError{h.errorCode}
+	}
+	if h.command != TCPCommandFrameSend {
+		return 0, TCPUnexpectedCommandError{TCPCommandFrameSend, h.command}
+	}
+	return copy(buf, payload), nil
+}
+
+func (t *tcpTransport) Close() error {
+	return t.conn.Close()
+}
+
+func (t *tcpTransport) Addresses() (local, remote DeviceAddress) {
+	return t.local, t.remote
+}
@@ -0,0 +1,160 @@
+package fins
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCPClient(t *testing.T) {
+	plcAddr := NewTCPAddress("127.0.0.1", 9610, 0, 10, 0)
+	s, e := NewTCPServerSimulator(plcAddr)
+	assert.Nil(t, e)
+	defer func() {
+		s.Close()
+		<-s.Done()
+	}()
+
+	c, e := NewTCPClient(NewTCPAddress("", 0, 0, 0, 0), plcAddr)
+	assert.Nil(t, e)
+	defer c.Close()
+
+	toWrite := []uint16{5, 4, 3, 2, 1}
+	err := c.WriteWords(MemoryAreaDMWord, 100, toWrite)
+	assert.Nil(t, err)
+
+	vals, err := c.ReadWords(MemoryAreaDMWord, 100, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, toWrite, vals)
+
+	err = c.WriteBits(MemoryAreaDMBit, 10, 2, []bool{true, false, true})
+	assert.Nil(t, err)
+
+	bs, err := c.ReadBits(MemoryAreaDMBit, 10, 2, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true, false, true}, bs)
+
+	local, remote := c.transport.Addresses()
+	assert.Equal(t, byte(1), local.node, "first free node should be allocated")
+	assert.Equal(t, byte(10), remote.node)
+
+	// reconnect after Close
+	c.Close()
+	vals, err = c.ReadWords(MemoryAreaDMWord, 100, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, toWrite, vals)
+}
+
+func TestTCPClient_nodeAddress(t *testing.T) {
+	plcAddr := NewTCPAddress("127.0.0.1", 9611, 0, 10, 0)
+	s, e := NewTCPServerSimulator(plcAddr)
+	assert.Nil(t, e)
+	defer func() {
+		s.Close()
+		<-s.Done()
+	}()
+
+	c1, _ := NewTCPClient(NewTCPAddress("", 0, 0, 20, 0), plcAddr)
+	defer c1.Close()
+	_, err := c1.ReadWords(MemoryAreaDMWord, 0, 1)
+	assert.Nil(t, err)
+
+	c2, _ := NewTCPClient(NewTCPAddress("", 0, 0, 20, 0), plcAddr)
+	defer c2.Close()
+	_, err = c2.ReadWords(MemoryAreaDMWord, 0, 1)
+	assert.Equal(t, TCPErrorCodeError{TCPErrorCodeNodeAlreadyConnected}, err)
+
+	c3, _ := NewTCPClient(NewTCPAddress("", 0, 0, 10, 0), plcAddr)
+	defer c3.Close()
+	_, err = c3.ReadWords(MemoryAreaDMWord, 0, 1)
+	assert.Equal(t, TCPErrorCodeError{TCPErrorCodeSameNodeAddress}, err)
+
+	// node 20 is free again after c1 closed
+	c1.Close()
+	assert.Eventually(t, func() bool {
+		_, err = c2.ReadWords(MemoryAreaDMWord, 0, 1)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTCPServer_concurrentClients(t *testing.T) {
+	plcAddr := NewTCPAddress("127.0.0.1", 9612, 0, 10, 0)
+	s, e := NewTCPServerSimulator(plcAddr)
+	assert.Nil(t, e)
+	defer func() {
+		s.Close()
+		<-s.Done()
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, _ := NewTCPClient(NewTCPAddress("", 0, 0, 0, 0), plcAddr)
+			defer c.Close()
+			c.SetTimeoutMs(1000)
+			address := uint16(i * 10)
+			for j := 0; j < 20; j++ {
+				err := c.WriteWords(MemoryAreaDMWord, address, []uint16{uint16(j)})
+				assert.Nil(t, err)
+				vals, err := c.ReadWords(MemoryAreaDMWord, address, 1)
+				assert.Nil(t, err)
+				assert.Equal(t, []uint16{uint16(j)}, vals)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestTCPClient_badHeader(t *testing.T) {
+	plcAddr := NewTCPAddress("127.0.0.1", 9613, 0, 10, 0)
+	l, err := net.ListenTCP("tcp", plcAddr.tcpAddress)
+	assert.Nil(t, err)
+	defer l.Close()
+
+	// a PLC answering every command with a header of bad magic
+	closed := make(chan struct{}, 2)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if _, _, err := readTCPFrame(conn, nil); err != nil {
+					return
+				}
+				conn.Write(encodeTCPFrame(TCPCommandServerNodeAddressSend, TCPErrorCodeNormal, encodeNodeAddressData(1, 10)))
+				if _, _, err := readTCPFrame(conn, nil); err != nil {
+					return
+				}
+				conn.Write([]byte("BADMAGIC00000000"))
+				if _, _, err := readTCPFrame(conn, nil); errors.Is(err, io.EOF) {
+					closed <- struct{}{}
+				}
+			}()
+		}
+	}()
+
+	c, err := NewTCPClient(NewTCPAddress("", 0, 0, 0, 0), plcAddr)
+	assert.Nil(t, err)
+	defer c.Close()
+	c.SetTimeoutMs(200)
+
+	for i := 0; i < 2; i++ {
+		_, err = c.ReadWords(MemoryAreaDMWord, 0, 1)
+		assert.NotNil(t, err)
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			t.Fatal("client should close the connection after a bad header")
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package fins
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+)
+
+// TCPServer Omron FINS/TCP server (PLC emulator)
+// it is just for test, same as UDPServer. don't use in production
+type TCPServer struct {
+	simulator
+	addr     TCPAddress
+	listener *net.TCPListener
+	ch       chan struct{}
+	wg       sync.WaitGroup
+
+	cm     sync.Mutex
+	closed bool
+	conns  map[*net.TCPConn]struct{}
+	nodes  map[byte]*net.TCPConn // client node -> conn
+}
+
+func NewTCPServerSimulator(plcAddr TCPAddress) (*TCPServer, error) {
+	if plcAddr.tcpAddress == nil { // net.ListenTCP work on random port but I want it fails
+		return nil, EmptyPlcTCPAddress{}
+	}
+	s := new(TCPServer)
+	s.addr = plcAddr
+	s.initMemory()
+	s.ch = make(chan struct{})
+	s.conns = map[*net.TCPConn]struct{}{}
+	s.nodes = map[byte]*net.TCPConn{}
+	listener, err := net.ListenTCP("tcp", plcAddr.tcpAddress)
+	if err != nil {
+		return nil, err
+	}
+	s.listener = listener
+
+	go func() {
+		defer close(s.ch)
+		defer s.wg.Wait()
+		for {
+			conn, er := listener.AcceptTCP()
+			if er != nil {
+				if !errors.Is(er, net.ErrClosed) {
+					s.printFinsPacketError("fins server %v: failed to accept: %s", plcAddr.tcpAddress, er)
+				}
+				return
+			}
+			if !s.addConn(conn) {
+				conn.Close()
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer s.removeConn(conn)
+				s.serve(conn)
+			}()
+		}
+	}()
+
+	return s, nil
+}
+
+// serve does the node address handshake then handles FINS frames until conn closed
+func (s *TCPServer) serve(conn *net.TCPConn) {
+	defer conn.Close()
+	remote := conn.RemoteAddr().String()
+	buf := make([]byte, tcpMaxPayloadSize)
+
+	h, payload, err := readTCPFrame(conn, buf)
+	if err != nil {
+		s.handleServeError(conn, err)
+		return
+	}
+	s.printPacket("read from "+remote, encodeTCPFrame(h.command, h.errorCode, payload))
+	if h.command != TCPCommandClientNodeAddressSend || len(payload) != 4 {
+		s.writeTCPFrame(conn, TCPCommandFrameSendErrorNotification, TCPErrorCodeCommandNotSupported, nil)
+		return
+	}
+	clientNode, errorCode := s.allocateNode(conn, payload)
+	if errorCode != TCPErrorCodeNormal {
+		s.writeTCPFrame(conn, TCPCommandServerNodeAddressSend, errorCode, nil)
+		return
+	}
+	s.writeTCPFrame(conn, TCPCommandServerNodeAddressSend, TCPErrorCodeNormal,
+		encodeNodeAddressData(clientNode, s.addr.deviceAddress.node))
+
+	for {
+		h, payload, err = readTCPFrame(conn, buf)
+		if err != nil {
+			s.handleServeError(conn, err)
+			return
+		}
+		if h.command != TCPCommandFrameSend {
+			s.writeTCPFrame(conn, TCPCommandFrameSendErrorNotification, TCPErrorCodeCommandNotSupported, nil)
+			continue
+		}
+		if len(payload) < minRequestPacketSize {
+			s.printFinsPacketError("fins server %v: minRequestPacketSize is %d, got %d: % X", s.addr.tcpAddress, minRequestPacketSize, len(payload), payload)
+			continue
+		}
+		s.printPacket("read from "+remote, payload)
+		respPacket := s.handleRequestPacket(payload)
+		s.printPacket("write to "+remote, respPacket)
+		if !s.writeTCPFrame(conn, TCPCommandFrameSend, TCPErrorCodeNormal, respPacket) {
+			return
+		}
+	}
+}
+
+// allocateNode registers conn with the client node it asks for, or the first free node if it asks for 0
+func (s *TCPServer) allocateNode(conn *net.TCPConn, nodeAddressData []byte) (byte, uint32) {
+	s.cm.Lock()
+	defer s.cm.Unlock()
+	requested := nodeAddressData[3]
+	if nodeAddressData[0] != 0 || nodeAddressData[1] != 0 || nodeAddressData[2] != 0 || requested == 0xff {
+		return 0, TCPErrorCodeClientNodeOutOfRange
+	}
+	serverNode := s.addr.deviceAddress.node
+	if requested == 0 {
+		for node := byte(1); node < 0xff; node++ {
+			if _, used := s.nodes[node]; !used && node != serverNode {
+				requested = node
+				break
+			}
+		}
+		if requested == 0 {
+			return 0, TCPErrorCodeAllNodeAddressesInUse
+		}
+	} else if requested == serverNode {
+		return 0, TCPErrorCodeSameNodeAddress
+	} else if _, used := s.nodes[requested]; used {
+		return 0, TCPErrorCodeNodeAlreadyConnected
+	}
+	s.nodes[requested] = conn
+	return requested, TCPErrorCodeNormal
+}
+
+func (s *TCPServer) addConn(conn *net.TCPConn) bool {
+	s.cm.Lock()
+	defer s.cm.Unlock()
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+// removeConn forgets conn and releases its client node
+func (s *TCPServer) removeConn(conn *net.TCPConn) {
+	s.cm.Lock()
+	defer s.cm.Unlock()
+	delete(s.conns, conn)
+	for node, c := range s.nodes {
+		if c == conn {
+			delete(s.nodes, node)
+		}
+	}
+}
+
+func (s *TCPServer) writeTCPFrame(conn *net.TCPConn, command, errorCode uint32, payload []byte) bool {
+	_, err := conn.Write(encodeTCPFrame(command, errorCode, payload))
+	if err != nil {
+		s.printFinsPacketError("fins server %v: failed to write FINS/TCP frame: %s", s.addr.tcpAddress, err)
+		return false
+	}
+	return true
+}
+
+func (s *TCPServer) handleServeError(conn *net.TCPConn, err error) {
+	if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+		return
+	}
+	var he TCPHeaderError
+	if errors.As(err, &he) {
+		s.writeTCPFrame(conn, TCPCommandFrameSendErrorNotification, TCPErrorCodeHeaderNotFINS, nil)
+	}
+	s.printFinsPacketError("fins server %v: failed to read FINS/TCP frame from %s: %s", s.addr.tcpAddress, conn.RemoteAddr(), err)
+}
+
+// Close Closes the FINS/TCP server and all client connections
+func (s *TCPServer) Close() {
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	s.cm.Lock()
+	defer s.cm.Unlock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *TCPServer) Done() <-chan struct{} {
+	return s.ch
+}
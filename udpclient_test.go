@@ -67,7 +67,7 @@ func test1(t *testing.T, c *UDPClient) {
 
 	v, err := c.ReadString(MemoryAreaDMWord, 12, 1)
 	assert.Nil(t, err)
-	assert.Equal(t, "12", v)
+	assert.Equal(t, "34", v) // D10="ф" D11="12" D12="34"
 
 	v, err = c.ReadString(MemoryAreaDMWord, 10, 3)
 	assert.Nil(t, err)
@@ -8,25 +8,137 @@ import (
 )
 
 func checkIsWordMemoryArea(memoryArea byte) error {
-	if memoryArea == MemoryAreaDMWord ||
-		memoryArea == MemoryAreaARWord ||
-		memoryArea == MemoryAreaHRWord ||
-		memoryArea == MemoryAreaWRWord {
+	if memoryAreaItemSize[memoryArea] == 2 {
 		return nil
 	}
 	return IncompatibleMemoryAreaError{memoryArea}
 }
 
 func checkIsBitMemoryArea(memoryArea byte) error {
-	if memoryArea == MemoryAreaDMBit ||
-		memoryArea == MemoryAreaARBit ||
+	if memoryAreaItemSize[memoryArea] == 1 {
+		return nil
+	}
+	return IncompatibleMemoryAreaError{memoryArea}
+}
+
+// checkIsDoubleWordMemoryArea only index registers are read and written in double words
+func checkIsDoubleWordMemoryArea(memoryArea byte) error {
+	if memoryAreaItemSize[memoryArea] == 4 {
+		return nil
+	}
+	return IncompatibleMemoryAreaError{memoryArea}
+}
+
+// checkIsWritableMemoryArea task flags, clock pulses and condition flags are read only
+func checkIsWritableMemoryArea(memoryArea byte) error {
+	if _, ok := memoryAreaReadOnly[memoryArea]; ok {
+		return ReadOnlyMemoryAreaError{memoryArea}
+	}
+	return nil
+}
+
+// checkIsForceableMemoryArea bits of I/O, work and holding area and timer/counter completion flags can be forced
+func checkIsForceableMemoryArea(memoryArea byte) error {
+	if memoryArea == MemoryAreaCIOBit ||
+		memoryArea == MemoryAreaWRBit ||
 		memoryArea == MemoryAreaHRBit ||
-		memoryArea == MemoryAreaWRBit {
+		memoryArea == MemoryAreaTimerCounterCompletionFlag {
 		return nil
 	}
 	return IncompatibleMemoryAreaError{memoryArea}
 }
 
+// getBit returns bit i of big endian words, bit 0 is the lowest bit of the first word
+func getBit(words []byte, i int) byte {
+	b := words[i/16*2+1-i%16/8]
+	return b >> (i % 8) & 0x01
+}
+
+func setBit(words []byte, i int, v byte) {
+	idx := i/16*2 + 1 - i%16/8
+	if v&0x01 != 0 {
+		words[idx] |= 1 << (i % 8)
+	} else {
+		words[idx] &^= 1 << (i % 8)
+	}
+}
+
+// pipelineDepth max frames of one split read waiting for responses at the same time
+const pipelineDepth = 8
+
+// doubleWordsPerFrame double words fit in a frame of maxWords words, at least one
+func doubleWordsPerFrame(maxWords int) int {
+	if maxWords < 2 {
+		return 1
+	}
+	return maxWords / 2
+}
+
+// frameChunks starts of chunks of at most size items of count items, at least one chunk even if count is 0
+func frameChunks(count, size int) []int {
+	starts := []int{0}
+	for start := size; start < count; start += size {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// pipeline calls do with each start concurrently, at most pipelineDepth at the same time.
+// each call sends its own frame with its own SID, results are in the order of starts.
+// the first failed call cancels ctx given to the others, starts not called yet are skipped and its error is returned
+func pipeline[T any](ctx context.Context, starts []int, do func(ctx context.Context, start int) (T, error)) ([]T, error) {
+	results := make([]T, len(starts))
+	if len(starts) == 1 {
+		r, err := do(ctx, starts[0])
+		if err != nil {
+			return nil, err
+		}
+		results[0] = r
+		return results, nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	sem := make(chan struct{}, pipelineDepth)
+	for i, start := range starts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i, start int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			r, err := do(ctx, start)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = r
+		}(i, start)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err // canceled by caller before all starts were called
+	}
+	return results, nil
+}
+
 type atomicByte struct {
 	m sync.Mutex
 	v byte
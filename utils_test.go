@@ -1,10 +1,14 @@
 package fins
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_singleflightOne_do(t *testing.T) {
@@ -59,3 +63,45 @@ func f2() {
 	time.Sleep(time.Second)
 	fmt.Println(1)
 }
+
+func Test_frameChunks(t *testing.T) {
+	assert.Equal(t, []int{0}, frameChunks(0, 996))
+	assert.Equal(t, []int{0}, frameChunks(996, 996))
+	assert.Equal(t, []int{0, 996, 1992}, frameChunks(2500, 996))
+}
+
+func Test_pipeline(t *testing.T) {
+	ctx := context.Background()
+	starts := frameChunks(100, 1)
+	results, err := pipeline(ctx, starts, func(ctx context.Context, start int) (int, error) {
+		time.Sleep(time.Duration(100-start) * 10 * time.Microsecond)
+		return start * 2, nil
+	})
+	assert.Nil(t, err)
+	for i, r := range results {
+		assert.Equal(t, i*2, r)
+	}
+
+	// a failed chunk cancels the ones waiting for responses and skips the rest
+	var called atomic.Int32
+	_, err = pipeline(ctx, starts, func(ctx context.Context, start int) (int, error) {
+		called.Add(1)
+		if start < 10 {
+			return start, nil
+		}
+		if start == 10 {
+			return 0, fmt.Errorf("chunk %d", start)
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	assert.EqualError(t, err, "chunk 10")
+	assert.LessOrEqual(t, called.Load(), int32(11+pipelineDepth))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = pipeline(canceled, starts, func(ctx context.Context, start int) (int, error) {
+		return start, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}